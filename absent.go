@@ -12,7 +12,9 @@ var (
 )
 
 // Nil is a validation rule that checks if a value is nil.
-// It is the opposite of NotNil rule
+// It is the opposite of NotNil rule.
+// Only nil values pass the rule, even if a value is empty by its own definition (see IsEmpty).
+// Use Empty to check if a value is nil or empty.
 var Nil = absentRule{condition: true, skipNil: false}
 
 // Empty checks if a not nil value is empty.
@@ -0,0 +1,80 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+)
+
+// maxCoercedExponent is the maximum absolute decimal exponent accepted in a numeric string.
+const maxCoercedExponent = 10000
+
+var (
+	// ErrNotNumeric is the error that returns when a value cannot be coerced into a number.
+	ErrNotNumeric = NewError("validation_not_numeric", "must be a valid number")
+
+	numericStringRegex = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE]([+-]?\d+))?$`)
+)
+
+// coercionKey is the context key used to enable numeric coercion.
+type coercionKey struct{}
+
+// WithCoercion returns a copy of the context that enables numeric coercion for the numeric rules
+// (Min, Max, Between, MultipleOf and In) validating values with the context, as if Coerce() is called on each of them.
+func WithCoercion(ctx context.Context) context.Context {
+	return context.WithValue(ctx, coercionKey{}, true)
+}
+
+// coercionEnabled checks if numeric coercion is enabled by the given context.
+func coercionEnabled(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	enabled, _ := ctx.Value(coercionKey{}).(bool)
+	return enabled
+}
+
+// parseNumericString parses a string in decimal notation into an exact rational number.
+func parseNumericString(s string) (*big.Rat, error) {
+	m := numericStringRegex.FindStringSubmatch(s)
+	if m == nil {
+		return nil, ErrNotNumeric
+	}
+	if m[3] != "" {
+		if exp, err := strconv.Atoi(m[3]); err != nil || exp > maxCoercedExponent || exp < -maxCoercedExponent {
+			return nil, ErrNotNumeric
+		}
+	}
+	rat, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, ErrNotNumeric
+	}
+	return rat, nil
+}
+
+// isEmptyString checks if a value is an empty string.
+func isEmptyString(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.String && v.Len() == 0
+}
+
+// numberEqual checks if an element is a number that is equal to the given value by value.
+// Both the element and the value are coerced, so that numeric strings and json.Number elements match too.
+func numberEqual(element, value interface{}) bool {
+	e, err := toNumber(element, true)
+	if err != nil {
+		return false
+	}
+	v, err := toNumber(value, true)
+	if err != nil {
+		return false
+	}
+	c, err := compareNumbers(e, v)
+	return err == nil && c == 0
+}
@@ -0,0 +1,76 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCoercion(t *testing.T) {
+	var doc map[string]interface{}
+	err := json.Unmarshal([]byte(`{"age": 17, "size": 10, "level": 2}`), &doc)
+	assert.Nil(t, err)
+
+	rules := []*KeyRules{
+		Key("age", Min(18)),
+		Key("size", MultipleOf(4)),
+		Key("level", In(1, 2, 3)),
+	}
+
+	err = Validate(doc, Map(rules...))
+	assert.EqualError(t, err, "age: cannot convert float64 to int64; level: must be a valid value; size: cannot convert float64 to int64.")
+
+	ctx := WithCoercion(context.Background())
+	err = ValidateWithContext(ctx, doc, Map(rules...))
+	assert.EqualError(t, err, "age: must be no less than 18; size: must be multiple of 4.")
+
+	// the coercion applies to nested rules
+	err = ValidateWithContext(ctx, []interface{}{float64(20), "17"}, Each(Min(18)))
+	assert.EqualError(t, err, "1: must be no less than 18.")
+
+	assert.False(t, coercionEnabled(nil))
+	assert.False(t, coercionEnabled(context.Background()))
+	assert.True(t, coercionEnabled(ctx))
+}
+
+func TestParseNumericString(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+		valid    bool
+	}{
+		{"0", "0", true},
+		{"-12", "-12", true},
+		{"+1.50", "3/2", true},
+		{".5", "1/2", true},
+		{"5.", "5", true},
+		{"2.5e-3", "1/400", true},
+		{"1E3", "1000", true},
+		{"1/3", "", false},
+		{"0x10", "", false},
+		{" 1", "", false},
+		{"1_000", "", false},
+		{"Inf", "", false},
+		{"NaN", "", false},
+		{"1e", "", false},
+		{"1e10001", "", false},
+		{"1e-10001", "", false},
+		{"1e99999999999999999999", "", false},
+	}
+	for _, test := range tests {
+		r, err := parseNumericString(test.value)
+		if test.valid {
+			if assert.Nil(t, err, test.value) {
+				assert.Equal(t, test.expected, r.RatString(), test.value)
+			}
+		} else {
+			assert.Equal(t, ErrNotNumeric, err, test.value)
+		}
+	}
+}
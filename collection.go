@@ -0,0 +1,439 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrNotUnique is the error that returns when an element of a collection duplicates a previous element.
+	ErrNotUnique = NewError("validation_not_unique", "must be unique")
+	// ErrNotSorted is the error that returns when an element of a collection is less than the previous element.
+	ErrNotSorted = NewError("validation_not_sorted", "must not be less than the previous element")
+	// ErrCountTooMany is the error that returns when too many elements of a collection match a condition.
+	ErrCountTooMany = NewError("validation_count_too_many", "the number of matching elements must be no more than {{.max}}")
+	// ErrCountTooFew is the error that returns when too few elements of a collection match a condition.
+	ErrCountTooFew = NewError("validation_count_too_few", "the number of matching elements must be no less than {{.min}}")
+	// ErrCountInvalid is the error that returns when the number of the matching elements of a collection is not exact.
+	ErrCountInvalid = NewError("validation_count_invalid", "the number of matching elements must be exactly {{.min}}")
+	// ErrCountOutOfRange is the error that returns when the number of the matching elements of a collection is out of range.
+	ErrCountOutOfRange = NewError("validation_count_out_of_range", "the number of matching elements must be between {{.min}} and {{.max}}")
+	// ErrCountNoneRequired is the error that returns when an element of a collection matches a condition no element may match.
+	ErrCountNoneRequired = NewError("validation_count_none_required", "must have no matching elements")
+
+	// ErrMapNotSortable is the error that the Sorted rule is used to validate a map.
+	ErrMapNotSortable = errors.New("a map has no order and cannot be sorted")
+)
+
+type (
+	// UniqueRule is a validation rule that checks if the elements of a collection are unique.
+	UniqueRule struct {
+		key func(elem interface{}) interface{}
+		err Error
+	}
+
+	// SortedRule is a validation rule that checks if the elements of a slice or an array are sorted.
+	SortedRule struct {
+		less func(a, b interface{}) bool
+		err  Error
+	}
+
+	// CountRule is a validation rule that checks if the number of the elements of a collection
+	// matching a condition is within the specified range.
+	CountRule struct {
+		pred     func(elem interface{}) bool
+		min, max int
+		err      Error
+	}
+
+	// SumRule is a validation rule that validates the sum of the numbers extracted from the elements of a collection.
+	SumRule struct {
+		extract func(elem interface{}) interface{}
+		rules   []Rule
+	}
+
+	// collectionEntry is an element of a collection together with its error key.
+	collectionEntry struct {
+		key   string
+		value interface{}
+	}
+)
+
+// Unique returns a validation rule that checks if the elements of a slice, an array or the values of a map
+// are unique. Pointers are compared by the values they point to, and nil elements are ignored. Values of types
+// that are not comparable, such as slices, are compared with reflect.DeepEqual. Each element that duplicates
+// a previous element is reported with ErrNotUnique under its index or key, for example,
+//
+//	err := validation.Validate([]string{"a", "b", "a"}, validation.Unique())
+//	// 2: must be unique.
+//
+// The values of a map are visited in the order of their keys as strings. The key or index of the first occurrence
+// is available as the "first" parameter of the error. An empty collection is considered valid.
+func Unique() UniqueRule {
+	return UniqueRule{err: ErrNotUnique}
+}
+
+// UniqueBy returns a validation rule that checks if the keys returned by the given function for the elements
+// of a slice, an array or the values of a map are unique. It is used to check a single property of the elements,
+// such as "no two line items with the same SKU":
+//
+//	validation.Field(&o.Items, validation.UniqueBy(func(elem interface{}) interface{} {
+//	    return elem.(LineItem).SKU
+//	})),
+//
+// An element whose key is nil is ignored. The duplicates are reported in the same way as Unique().
+func UniqueBy(key func(elem interface{}) interface{}) UniqueRule {
+	return UniqueRule{key: key, err: ErrNotUnique}
+}
+
+// Validate checks if the given value is valid or not.
+func (r UniqueRule) Validate(value interface{}) error {
+	entries, _, err := collectionEntries(value)
+	if err != nil {
+		return err
+	}
+
+	var (
+		// seen holds the keys of the first occurrences of comparable values
+		seen = map[interface{}]string{}
+		// others holds the first occurrences of values that are not comparable
+		others []collectionEntry
+		errs   = Errors{}
+	)
+	for _, e := range entries {
+		k := e.value
+		if r.key != nil {
+			k = r.key(k)
+		}
+		k, isNil := Indirect(k)
+		if isNil {
+			continue
+		}
+		if t, ok := k.(time.Time); ok {
+			// times are compared by the instants they represent
+			k = t.Round(0).UTC()
+		}
+		first, found := "", false
+		if reflect.TypeOf(k).Comparable() {
+			if first, found = seen[k]; !found {
+				seen[k] = e.key
+			}
+		} else {
+			for _, o := range others {
+				if reflect.DeepEqual(o.value, k) {
+					first, found = o.key, true
+					break
+				}
+			}
+			if !found {
+				others = append(others, collectionEntry{key: e.key, value: k})
+			}
+		}
+		if found {
+			errs[e.key] = r.err.SetParams(map[string]interface{}{"first": first})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Error sets the error message for the rule.
+func (r UniqueRule) Error(message string) UniqueRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r UniqueRule) ErrorObject(err Error) UniqueRule {
+	r.err = err
+	return r
+}
+
+// Sorted returns a validation rule that checks if the elements of a slice or an array are sorted according to
+// the given less function, which reports whether a must sort before b. If less is nil, the elements are sorted
+// in ascending order: strings are compared lexically, times chronologically, and numbers by value in the same
+// way as Min(). For example,
+//
+//	validation.Field(&r.IDs, validation.Sorted(nil)),
+//
+// Each element that is less than the previous element is reported with ErrNotSorted under its index.
+// Equal elements are considered sorted; use Unique() to disallow them. Nil elements are ignored.
+// An empty collection is considered valid. Maps have no order and cannot be validated by this rule.
+func Sorted(less func(a, b interface{}) bool) SortedRule {
+	return SortedRule{less: less, err: ErrNotSorted}
+}
+
+// Validate checks if the given value is valid or not.
+func (r SortedRule) Validate(value interface{}) error {
+	entries, isMap, err := collectionEntries(value)
+	if err != nil {
+		return err
+	}
+	if isMap {
+		return NewInternalError(ErrMapNotSortable)
+	}
+
+	errs := Errors{}
+	var prev *collectionEntry
+	for i, e := range entries {
+		if _, isNil := Indirect(e.value); isNil {
+			continue
+		}
+		if prev != nil {
+			less, err := r.compare(e.value, prev.value)
+			if err != nil {
+				return err
+			}
+			if less {
+				errs[e.key] = r.err.SetParams(map[string]interface{}{"previous": prev.key})
+			}
+		}
+		prev = &entries[i]
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// compare reports whether a is less than b.
+func (r SortedRule) compare(a, b interface{}) (bool, error) {
+	if r.less != nil {
+		return r.less(a, b), nil
+	}
+	a, _ = Indirect(a)
+	b, _ = Indirect(b)
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Before(tb), nil
+		}
+	}
+	sa, aIsString := a.(string)
+	sb, bIsString := b.(string)
+	if aIsString && bIsString {
+		return sa < sb, nil
+	}
+	c, err := compareValues(a, b, false)
+	return c < 0, err
+}
+
+// Error sets the error message for the rule.
+func (r SortedRule) Error(message string) SortedRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r SortedRule) ErrorObject(err Error) SortedRule {
+	r.err = err
+	return r
+}
+
+// CountWhere returns a validation rule that checks if the number of the elements of a slice, an array or
+// the values of a map for which the given function returns true is within the specified range.
+// If max is 0, it means there is no upper bound for the number, unless min is 0 as well, which means
+// no element may match. For example, to allow at most one primary address,
+//
+//	validation.Field(&c.Addresses, validation.CountWhere(func(elem interface{}) bool {
+//	    return elem.(Address).Primary
+//	}, 0, 1)),
+//
+// The number of the matching elements is available as the "count" parameter of the error.
+// An empty collection is considered valid. Use the Required rule to make sure a collection is not empty.
+func CountWhere(pred func(elem interface{}) bool, min, max int) CountRule {
+	return CountRule{pred: pred, min: min, max: max, err: buildCountRuleError(min, max)}
+}
+
+// Validate checks if the given value is valid or not.
+func (r CountRule) Validate(value interface{}) error {
+	entries, _, err := collectionEntries(value)
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+
+	n := 0
+	for _, e := range entries {
+		if r.pred(e.value) {
+			n++
+		}
+	}
+	if r.min > 0 && n < r.min || r.max > 0 && n > r.max || r.min == 0 && r.max == 0 && n > 0 {
+		return r.err.SetParams(map[string]interface{}{"min": r.min, "max": r.max, "count": n})
+	}
+	return nil
+}
+
+// Error sets the error message for the rule.
+func (r CountRule) Error(message string) CountRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r CountRule) ErrorObject(err Error) CountRule {
+	r.err = err
+	return r
+}
+
+func buildCountRuleError(min, max int) (err Error) {
+	switch {
+	case min == 0 && max > 0:
+		err = ErrCountTooMany
+	case min > 0 && max == 0:
+		err = ErrCountTooFew
+	case min > 0 && max > 0 && min == max:
+		err = ErrCountInvalid
+	case min > 0 && max > 0:
+		err = ErrCountOutOfRange
+	default:
+		err = ErrCountNoneRequired
+	}
+
+	return err.SetParams(map[string]interface{}{"min": min, "max": max})
+}
+
+// SumOf returns a validation rule that validates the sum of the numbers returned by the given function for
+// the elements of a slice, an array or the values of a map with the given rules. If the function is nil,
+// the elements themselves are summed. For example, to make sure the shares of an allocation add up to 100,
+//
+//	validation.Field(&a.Items, validation.SumOf(func(elem interface{}) interface{} {
+//	    return elem.(Item).Share
+//	}, validation.Min(100), validation.Max(100))),
+//
+// The sum of integers is an int64, the sum of unsigned integers is a uint64, and the sum of both is an int64,
+// or a uint64 if it is too large for an int64. An integer sum that overflows these types is a *big.Int, which
+// is compared by rules such as Max(big.NewInt(100)) or Max(100).Coerce(). The sum of numbers including floats
+// is a float64, and the sum of other numbers, such as *big.Rat values, is a *big.Rat. The numbers are added
+// exactly, regardless of their types. Nil numbers are ignored. The sum of an empty collection is a zero int64,
+// which is validated like other sums.
+func SumOf(extract func(elem interface{}) interface{}, rules ...Rule) SumRule {
+	return SumRule{extract: extract, rules: rules}
+}
+
+// Validate checks if the given value is valid or not.
+func (r SumRule) Validate(value interface{}) error {
+	return r.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r SumRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	return r.validateWithState(ctx, stateOf(ctx), value)
+}
+
+func (r SumRule) validateWithState(ctx context.Context, st validationState, value interface{}) error {
+	entries, _, err := collectionEntries(value)
+	if err != nil {
+		return err
+	}
+
+	var (
+		sum   = new(big.Rat)
+		kinds sumKinds
+	)
+	for _, e := range entries {
+		v := e.value
+		if r.extract != nil {
+			v = r.extract(v)
+		}
+		v, isNil := Indirect(v)
+		if isNil {
+			continue
+		}
+		n, err := toNumber(v, false)
+		if err != nil {
+			return err
+		}
+		if n.rat == nil {
+			return fmt.Errorf("cannot sum %v", v)
+		}
+		sum.Add(sum, n.rat)
+		kinds.add(reflect.ValueOf(v).Kind())
+	}
+	return validate(ctx, st, kinds.total(sum), reflect.Value{}, r.rules)
+}
+
+// sumKinds records the kinds of the numbers added to a sum.
+type sumKinds struct {
+	signed, unsigned, float, other bool
+}
+
+// add records the kind of a number added to the sum.
+func (k *sumKinds) add(kind reflect.Kind) {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		k.signed = true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		k.unsigned = true
+	case reflect.Float32, reflect.Float64:
+		k.float = true
+	default:
+		k.other = true
+	}
+}
+
+// total returns the sum as a value of the type determined by the kinds of the numbers added to it. See SumOf().
+func (k sumKinds) total(sum *big.Rat) interface{} {
+	switch {
+	case k.other:
+		return sum
+	case k.float:
+		f, _ := sum.Float64()
+		return f
+	}
+	// the sum of integers is an integer
+	n := sum.Num()
+	switch {
+	case (k.signed || !k.unsigned) && n.IsInt64():
+		return n.Int64()
+	case k.unsigned && n.IsUint64():
+		return n.Uint64()
+	}
+	return new(big.Int).Set(n)
+}
+
+// collectionEntries returns the elements of a slice or an array, or the values of a map, together with
+// their error keys. The values of a map are ordered by their keys as strings. A pointer to a collection is dereferenced,
+// and a nil collection has no elements.
+func collectionEntries(value interface{}) ([]collectionEntry, bool, error) {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		keys := v.MapKeys()
+		entries := make([]collectionEntry, len(keys))
+		for i, k := range keys {
+			entries[i] = collectionEntry{key: getErrorKeyName(k.Interface()), value: getIterableInterface(v.MapIndex(k))}
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].key < entries[j].key
+		})
+		return entries, true, nil
+	case reflect.Slice, reflect.Array:
+		entries := make([]collectionEntry, v.Len())
+		for i := range entries {
+			entries[i] = collectionEntry{key: strconv.Itoa(i), value: getIterableInterface(v.Index(i))}
+		}
+		return entries, false, nil
+	case reflect.Invalid:
+		// a nil pointer to a collection
+		return nil, false, nil
+	}
+	return nil, false, errors.New("must be an iterable (map, slice or array)")
+}
@@ -0,0 +1,181 @@
+package validation
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lineItem struct {
+	SKU     string
+	Qty     int
+	Primary bool
+}
+
+func TestUnique(t *testing.T) {
+	a, b := "a", "a"
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		tag   string
+		value interface{}
+		err   string
+	}{
+		{"t1", nil, ""},
+		{"t2", []string{}, ""},
+		{"t3", []string{"a", "b", "c"}, ""},
+		{"t4", []string{"a", "b", "a", "b", "a"}, "2: must be unique; 3: must be unique; 4: must be unique."},
+		{"t5", [3]int{1, 2, 1}, "2: must be unique."},
+		{"t6", []*string{&a, nil, &b, nil}, "2: must be unique."},
+		{"t7", map[string]int{"x": 1, "y": 2, "z": 1}, "z: must be unique."},
+		{"t8", [][]int{{1}, {2}, {1}}, "2: must be unique."},
+		{"t9", []time.Time{t0, t0.In(time.FixedZone("X", 3600))}, "1: must be unique."},
+		{"t10", &[]int{1, 1}, "1: must be unique."},
+		{"t11", "abc", "must be an iterable (map, slice or array)"},
+	}
+	for _, test := range tests {
+		err := Validate(test.value, Unique())
+		assertError(t, test.err, err, test.tag)
+	}
+
+	err := Unique().Validate([]int{1, 2, 1})
+	if assert.Error(t, err) {
+		e := err.(Errors)["2"].(Error)
+		assert.Equal(t, "0", e.Params()["first"])
+		assert.Equal(t, "validation_not_unique", e.Code())
+	}
+
+	err = Validate([]int{1, 1}, Unique().Error("duplicated"))
+	assertError(t, "1: duplicated.", err, "t12")
+}
+
+func TestUniqueBy(t *testing.T) {
+	items := []lineItem{{SKU: "a"}, {SKU: "b"}, {SKU: "a"}, {}}
+	sku := func(elem interface{}) interface{} {
+		if s := elem.(lineItem).SKU; s != "" {
+			return s
+		}
+		return nil
+	}
+	err := Validate(items, UniqueBy(sku))
+	assertError(t, "2: must be unique.", err, "t1")
+
+	items[2].SKU = "c"
+	assert.NoError(t, Validate(items, UniqueBy(sku)))
+
+	// the rule reports its errors under the field
+	o := struct{ Items []lineItem }{[]lineItem{{SKU: "a"}, {SKU: "a"}}}
+	err = ValidateStruct(&o, Field(&o.Items, UniqueBy(sku)))
+	assertError(t, "Items: (1: must be unique.).", err, "t2")
+}
+
+func TestSorted(t *testing.T) {
+	tests := []struct {
+		tag   string
+		value interface{}
+		err   string
+	}{
+		{"t1", nil, ""},
+		{"t2", []int{1, 2, 2, 5}, ""},
+		{"t3", []int{1, 3, 2, 4, 0}, "2: must not be less than the previous element; 4: must not be less than the previous element."},
+		{"t4", []string{"a", "c", "b"}, "2: must not be less than the previous element."},
+		{"t5", []float64{1.5, 1.25}, "1: must not be less than the previous element."},
+		{"t6", []*big.Int{big.NewInt(2), nil, big.NewInt(1)}, "2: must not be less than the previous element."},
+		{"t7", []time.Time{time.Unix(10, 0), time.Unix(5, 0)}, "1: must not be less than the previous element."},
+		{"t8", []interface{}{1, "a"}, "cannot convert string to a number"},
+		{"t9", 1, "must be an iterable (map, slice or array)"},
+	}
+	for _, test := range tests {
+		err := Validate(test.value, Sorted(nil))
+		assertError(t, test.err, err, test.tag)
+	}
+
+	byQty := func(a, b interface{}) bool { return a.(lineItem).Qty < b.(lineItem).Qty }
+	err := Validate([]lineItem{{Qty: 2}, {Qty: 1}}, Sorted(byQty))
+	assertError(t, "1: must not be less than the previous element.", err, "t10")
+	if assert.Error(t, err) {
+		assert.Equal(t, "0", err.(Errors)["1"].(Error).Params()["previous"])
+	}
+
+	err = Validate(map[string]int{"a": 1}, Sorted(nil))
+	if assert.Error(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+	}
+}
+
+func TestCountWhere(t *testing.T) {
+	primary := func(elem interface{}) bool { return elem.(lineItem).Primary }
+	items := []lineItem{{Primary: true}, {}, {Primary: true}}
+	tests := []struct {
+		tag      string
+		min, max int
+		value    interface{}
+		err      string
+	}{
+		{"t1", 0, 1, items, "the number of matching elements must be no more than 1"},
+		{"t2", 0, 2, items, ""},
+		{"t3", 3, 0, items, "the number of matching elements must be no less than 3"},
+		{"t4", 1, 1, items, "the number of matching elements must be exactly 1"},
+		{"t5", 3, 4, items, "the number of matching elements must be between 3 and 4"},
+		{"t6", 0, 0, items, "must have no matching elements"},
+		{"t7", 1, 1, []lineItem{}, ""},
+		{"t8", 1, 1, map[string]lineItem{"x": {Primary: true}}, ""},
+		{"t9", 1, 1, true, "must be an iterable (map, slice or array)"},
+	}
+	for _, test := range tests {
+		err := Validate(test.value, CountWhere(primary, test.min, test.max))
+		assertError(t, test.err, err, test.tag)
+	}
+
+	err := CountWhere(primary, 0, 1).Validate(items)
+	if assert.Error(t, err) {
+		assert.Equal(t, 2, err.(Error).Params()["count"])
+	}
+	err = CountWhere(primary, 0, 1).Error("only one address can be primary").Validate(items)
+	assertError(t, "only one address can be primary", err, "t10")
+}
+
+func TestSumOf(t *testing.T) {
+	qty := func(elem interface{}) interface{} { return elem.(lineItem).Qty }
+	items := []lineItem{{Qty: 2}, {Qty: 3}}
+	tests := []struct {
+		tag   string
+		value interface{}
+		rule  Rule
+		err   string
+	}{
+		{"t1", items, SumOf(qty, Max(5)), ""},
+		{"t2", items, SumOf(qty, Max(4)), "must be no greater than 4"},
+		{"t3", []lineItem{}, SumOf(qty, Min(1)), "must be no less than 1"},
+		{"t4", []uint8{200, 100}, SumOf(nil, Max(uint(300))), ""},
+		{"t5", []float64{0.5, 0.25}, SumOf(nil, Max(0.5)), "must be no greater than 0.5"},
+		{"t6", []interface{}{1, 0.5}, SumOf(nil, Min(1.5), Max(1.5)), ""},
+		{"t7", []*big.Rat{big.NewRat(1, 3), big.NewRat(2, 3), nil}, SumOf(nil, Max(big.NewRat(1, 1))), ""},
+		{"t8", map[string]int{"a": 1, "b": 2}, SumOf(nil, In(int64(3))), ""},
+		{"t9", []string{"1"}, SumOf(nil), "cannot convert string to a number"},
+		{"t10", 1, SumOf(nil), "must be an iterable (map, slice or array)"},
+		{"t12", []interface{}{uint(1), -5}, SumOf(nil, In(int64(-4))), ""},
+		{"t13", []interface{}{-5, uint(1)}, SumOf(nil, In(int64(-4))), ""},
+		{"t14", []interface{}{int64(-1), uint64(math.MaxUint64)}, SumOf(nil, In(uint64(math.MaxUint64-1))), ""},
+		{"t15", []interface{}{uint64(math.MaxUint64), int64(-1)}, SumOf(nil, In(uint64(math.MaxUint64-1))), ""},
+		{"t16", []interface{}{uint64(math.MaxUint64), int8(1)}, SumOf(nil, Max(uint64(math.MaxUint64)).Coerce()), "must be no greater than 18446744073709551615"},
+		{"t17", []int64{math.MaxInt64, 1}, SumOf(nil, Min(0).Coerce(), Max(int64(math.MaxInt64)).Coerce()), "must be no greater than 9223372036854775807"},
+		{"t18", []int64{math.MinInt64, -1}, SumOf(nil, Min(big.NewInt(math.MinInt64))), "must be no less than -9223372036854775808"},
+		{"t19", []uint64{math.MaxUint64, 1}, SumOf(nil, Max(uint64(math.MaxUint64)).Coerce()), "must be no greater than 18446744073709551615"},
+		{"t20", []int8{100, 100}, SumOf(nil, In(int64(200))), ""},
+		{"t11", items, SumOf(qty, By(func(value interface{}) error {
+			if value.(int64)%2 != 0 {
+				return errors.New("must be even")
+			}
+			return nil
+		})), "must be even"},
+	}
+	for _, test := range tests {
+		err := Validate(test.value, test.rule)
+		assertError(t, test.err, err, test.tag)
+	}
+}
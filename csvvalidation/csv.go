@@ -0,0 +1,422 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package csvvalidation provides the validation of CSV files whose columns are bound to rules by header name.
+package csvvalidation
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var (
+	// ErrColumnMissing is the error that returns when a required column is missing in the header.
+	ErrColumnMissing = validation.NewError("validation_csv_column_missing", "required column is missing")
+	// ErrColumnUnknown is the error that returns when the header has a column without rules.
+	ErrColumnUnknown = validation.NewError("validation_csv_column_unknown", "unknown column")
+	// ErrColumnDuplicate is the error that returns when a column appears more than once in the header.
+	ErrColumnDuplicate = validation.NewError("validation_csv_column_duplicate", "duplicate column")
+	// ErrFieldCount is the error that returns when a row has more fields than the header.
+	ErrFieldCount = validation.NewError("validation_csv_field_count", "must have no more than {{.max}} fields")
+	// ErrInt is the error that returns when a cell is not an integer.
+	ErrInt = validation.NewError("validation_csv_int", "must be an integer")
+	// ErrFloat is the error that returns when a cell is not a number.
+	ErrFloat = validation.NewError("validation_csv_float", "must be a number")
+	// ErrBool is the error that returns when a cell is not a boolean.
+	ErrBool = validation.NewError("validation_csv_bool", "must be a boolean")
+)
+
+// HeaderErrorKey is the key of the header errors in the validation.Errors returned by Errors.ToErrors().
+var HeaderErrorKey = "header"
+
+type (
+	// ColumnRules represents a rule set associated with a CSV column.
+	ColumnRules struct {
+		name     string
+		rules    []validation.Rule
+		parse    func(s string) (interface{}, error)
+		optional bool
+	}
+
+	// Validator validates the rows of a CSV file with the rules of its columns. See New().
+	Validator struct {
+		columns      []*ColumnRules
+		allowUnknown bool
+		maxErrors    int
+		onError      func(ctx context.Context, err CellError) error
+	}
+
+	// CellError represents the error of a cell of a CSV file. An error of a whole row has an empty column name.
+	CellError struct {
+		// Row is the one-based number of the row, not counting the header.
+		Row int
+		// Line is the one-based line number of the cell in the file.
+		Line int
+		// Column is the name of the column.
+		Column string
+		// Err is the validation error of the cell.
+		Err error
+	}
+
+	// Errors represents the errors of a CSV file.
+	Errors struct {
+		// Header holds the errors of the header keyed by column names.
+		Header validation.Errors
+		// Cells holds the errors of the cells. They are not collected if the errors are passed
+		// to a callback specified by OnError().
+		Cells []CellError
+		// Rows is the number of the rows that are read, not counting the header.
+		Rows int
+		// Stopped indicates that the validation is stopped because the error budget is exhausted.
+		Stopped bool
+	}
+)
+
+// Column associates a column of a CSV file with a list of validation rules. The column is bound by the
+// name in the header. The cells of the column are validated as strings, unless a parsing method such as
+// Int() is called to convert them before validation. For example,
+//
+//	csvvalidation.Column("email", validation.Required, is.Email)
+//	csvvalidation.Column("age", validation.Min(18)).Int()
+//
+// The column must be present in the header unless Optional() is called.
+func Column(name string, rules ...validation.Rule) *ColumnRules {
+	return &ColumnRules{name: name, rules: rules}
+}
+
+// Optional configures the column to be optional in the header.
+func (c *ColumnRules) Optional() *ColumnRules {
+	c.optional = true
+	return c
+}
+
+// Int configures the cells of the column to be parsed as int64 values before validation.
+// A cell that is not an integer is reported as ErrInt.
+func (c *ColumnRules) Int() *ColumnRules {
+	c.parse = func(s string) (interface{}, error) {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, ErrInt
+		}
+		return v, nil
+	}
+	return c
+}
+
+// Float configures the cells of the column to be parsed as float64 values before validation.
+// A cell that is not a number is reported as ErrFloat.
+func (c *ColumnRules) Float() *ColumnRules {
+	c.parse = func(s string) (interface{}, error) {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, ErrFloat
+		}
+		return v, nil
+	}
+	return c
+}
+
+// Bool configures the cells of the column to be parsed as bool values before validation.
+// The values accepted by strconv.ParseBool are supported. A cell that is not a boolean is reported as ErrBool.
+func (c *ColumnRules) Bool() *ColumnRules {
+	c.parse = func(s string) (interface{}, error) {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, ErrBool
+		}
+		return v, nil
+	}
+	return c
+}
+
+// Date configures the cells of the column to be parsed as time.Time values in UTC before validation,
+// using the given layout in the same way as validation.Date(). A cell that is not a valid date is reported
+// as validation.ErrDateInvalid. Use validation.Min() and validation.Max() to check the range of the dates.
+func (c *ColumnRules) Date(layout string) *ColumnRules {
+	c.parse = func(s string) (interface{}, error) {
+		v, err := time.ParseInLocation(layout, s, time.UTC)
+		if err != nil {
+			return nil, validation.ErrDateInvalid
+		}
+		return v, nil
+	}
+	return c
+}
+
+// value returns the value of a cell to be validated. An empty cell of a parsed column has a nil value.
+func (c *ColumnRules) value(cell string) (interface{}, error) {
+	if c.parse == nil {
+		return cell, nil
+	}
+	if cell = strings.TrimSpace(cell); cell == "" {
+		return nil, nil
+	}
+	return c.parse(cell)
+}
+
+// New returns a validator that validates CSV files with the given columns. The first row of a file
+// is the header, which binds the columns by name. The header must contain all columns that are not optional,
+// and no columns without rules unless AllowUnknownColumns() is called. For example,
+//
+//	err := csvvalidation.New(
+//	    csvvalidation.Column("email", validation.Required, is.Email),
+//	    csvvalidation.Column("age", validation.Min(18)).Int(),
+//	    csvvalidation.Column("joined", validation.Required).Date("2006-01-02").Optional(),
+//	).MaxErrors(100).Validate(file)
+//
+// The rows are read and validated one at a time, and a row with fewer fields than the header is
+// validated as if the missing fields were empty.
+func New(columns ...*ColumnRules) Validator {
+	return Validator{columns: columns}
+}
+
+// AllowUnknownColumns configures the validator to ignore the columns of the header that have no rules.
+func (v Validator) AllowUnknownColumns() Validator {
+	v.allowUnknown = true
+	return v
+}
+
+// MaxErrors sets the error budget of the validation, which stops reading the file after the given number of
+// cell errors. It also limits the number of the errors that are collected. If n is 0, the whole file is read.
+func (v Validator) MaxErrors(n int) Validator {
+	v.maxErrors = n
+	return v
+}
+
+// OnError sets the callback that is called with each cell error as soon as it is found. The errors passed
+// to the callback are not collected. If the callback returns an error, the validation is stopped
+// and the error is returned.
+func (v Validator) OnError(f func(ctx context.Context, err CellError) error) Validator {
+	v.onError = f
+	return v
+}
+
+// Validate reads and validates a CSV file from the given reader. It returns Errors if the file is invalid,
+// or the error of reading the file, such as a *csv.ParseError.
+func (v Validator) Validate(r io.Reader) error {
+	return v.ValidateCSV(nil, csv.NewReader(r))
+}
+
+// ValidateWithContext reads and validates a CSV file from the given reader with the given context.
+// The context-aware rules are used, and the validation is stopped with the error of the context
+// if the context is canceled.
+func (v Validator) ValidateWithContext(ctx context.Context, r io.Reader) error {
+	return v.ValidateCSV(ctx, csv.NewReader(r))
+}
+
+// ValidateCSV reads and validates a CSV file from the given csv.Reader, which can be configured with a different
+// separator or comment character. The context may be nil, in which case the context-aware rules are not used.
+// The reader is configured to allow rows with different numbers of fields and to reuse the records.
+func (v Validator) ValidateCSV(ctx context.Context, cr *csv.Reader) error {
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		header = nil
+	} else if err != nil {
+		return err
+	}
+	result := Errors{}
+	bound, errs := v.bind(header)
+	if len(errs) > 0 {
+		result.Header = errs
+		return result
+	}
+
+	cbCtx := ctx
+	if cbCtx == nil {
+		cbCtx = context.Background()
+	}
+	var count int
+	report := func(e CellError) (bool, error) {
+		count++
+		if v.onError != nil {
+			if err := v.onError(cbCtx, e); err != nil {
+				return true, err
+			}
+		} else {
+			result.Cells = append(result.Cells, e)
+		}
+		if v.maxErrors > 0 && count >= v.maxErrors {
+			result.Stopped = true
+			return true, nil
+		}
+		return false, nil
+	}
+
+	for {
+		if ctx != nil {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		result.Rows++
+
+		if len(record) > len(header) {
+			line, _ := cr.FieldPos(len(header))
+			e := CellError{Row: result.Rows, Line: line, Err: ErrFieldCount.SetParams(map[string]interface{}{"max": len(header)})}
+			if stop, err := report(e); err != nil {
+				return err
+			} else if stop {
+				break
+			}
+		}
+		stop := false
+		for _, b := range bound {
+			cell, line := "", 0
+			if b.index < len(record) {
+				cell = record[b.index]
+				line, _ = cr.FieldPos(b.index)
+			} else {
+				line, _ = cr.FieldPos(len(record) - 1)
+			}
+			err := validateCell(ctx, b.column, cell)
+			if err == nil {
+				continue
+			}
+			if ie, ok := err.(validation.InternalError); ok && ie.InternalError() != nil {
+				return err
+			}
+			if stop, err = report(CellError{Row: result.Rows, Line: line, Column: b.column.name, Err: err}); err != nil {
+				return err
+			} else if stop {
+				break
+			}
+		}
+		if stop {
+			break
+		}
+	}
+
+	if count > 0 {
+		return result
+	}
+	return nil
+}
+
+// boundColumn is a column bound to its index in the header.
+type boundColumn struct {
+	column *ColumnRules
+	index  int
+}
+
+// bind binds the columns to their indexes in the header and returns the errors of the header.
+func (v Validator) bind(header []string) ([]boundColumn, validation.Errors) {
+	errs := validation.Errors{}
+	indexes := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// remove the byte order mark written by some spreadsheet applications
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.TrimSpace(name)
+		if _, ok := indexes[name]; ok {
+			errs[name] = ErrColumnDuplicate
+		}
+		indexes[name] = i
+	}
+
+	var bound []boundColumn
+	known := make(map[string]bool, len(v.columns))
+	for _, c := range v.columns {
+		known[c.name] = true
+		if i, ok := indexes[c.name]; ok {
+			bound = append(bound, boundColumn{column: c, index: i})
+		} else if !c.optional {
+			errs[c.name] = ErrColumnMissing
+		}
+	}
+	if !v.allowUnknown {
+		for name := range indexes {
+			if _, ok := errs[name]; !ok && !known[name] {
+				errs[name] = ErrColumnUnknown
+			}
+		}
+	}
+	return bound, errs
+}
+
+// validateCell parses and validates the value of a cell.
+func validateCell(ctx context.Context, c *ColumnRules, cell string) error {
+	value, err := c.value(cell)
+	if err != nil {
+		return err
+	}
+	if ctx == nil {
+		return validation.Validate(value, c.rules...)
+	}
+	return validation.ValidateWithContext(ctx, value, c.rules...)
+}
+
+// Error returns the error string of CellError.
+func (e CellError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("row:%v: %v", e.Row, e.Err)
+	}
+	return fmt.Sprintf("row:%v, column:%q: %v", e.Row, e.Column, e.Err)
+}
+
+// Unwrap returns the error of the cell.
+func (e CellError) Unwrap() error {
+	return e.Err
+}
+
+// Error returns the error string of Errors.
+func (e Errors) Error() string {
+	if len(e.Header) > 0 {
+		return "invalid header: " + e.Header.Error()
+	}
+	var b strings.Builder
+	b.WriteString("invalid rows")
+	if e.Stopped {
+		b.WriteString(" (stopped after too many errors)")
+	}
+	for i, ce := range e.Cells {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(ce.Error())
+	}
+	return b.String()
+}
+
+// ToErrors converts the errors into validation.Errors. The errors of the header are keyed by HeaderErrorKey,
+// and the errors of the cells are keyed by the row numbers and the column names. The errors of whole rows
+// are keyed by the row numbers and validation.StructErrorKey.
+func (e Errors) ToErrors() validation.Errors {
+	errs := validation.Errors{}
+	if len(e.Header) > 0 {
+		errs[HeaderErrorKey] = e.Header
+	}
+	for _, ce := range e.Cells {
+		row := strconv.Itoa(ce.Row)
+		re, ok := errs[row].(validation.Errors)
+		if !ok {
+			re = validation.Errors{}
+			errs[row] = re
+		}
+		if ce.Column == "" {
+			re[validation.StructErrorKey] = ce.Err
+		} else {
+			re[ce.Column] = ce.Err
+		}
+	}
+	return errs
+}
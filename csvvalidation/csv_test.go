@@ -0,0 +1,129 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package csvvalidation
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/stretchr/testify/assert"
+)
+
+func newValidator() Validator {
+	return New(
+		Column("email", validation.Required, is.EmailFormat),
+		Column("age", validation.Min(int64(18))).Int(),
+		Column("score", validation.Max(1.0)).Float().Optional(),
+		Column("active").Bool().Optional(),
+		Column("joined", validation.Required, validation.Min(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))).Date("2006-01-02").Optional(),
+	)
+}
+
+func TestValidator_Validate(t *testing.T) {
+	tests := []struct {
+		tag   string
+		input string
+		err   string
+	}{
+		{"t1", "email,age\na@example.com,20\nb@example.com,\n", ""},
+		{"t2", "\ufeffemail, age ,joined\na@example.com,20,2020-01-01\n", ""},
+		{"t3", "email,age\na@example.com,17\nb,x\n", `invalid rows: row:1, column:"age": must be no less than 18; row:2, column:"email": must be a valid email address; row:2, column:"age": must be an integer`},
+		{"t4", "email,age,score,active,joined\n,20,1.5,maybe,1999-12-31\n", `invalid rows: row:1, column:"email": cannot be blank; row:1, column:"score": must be no greater than 1; row:1, column:"active": must be a boolean; row:1, column:"joined": must be no less than 2000-01-01 00:00:00 +0000 UTC`},
+		{"t5", "email,age,joined\na@example.com,20,2020/01/01\n", `invalid rows: row:1, column:"joined": must be a valid date`},
+		{"t6", "email,age,joined\na@example.com\n", `invalid rows: row:1, column:"joined": cannot be blank`},
+		{"t7", "email,age\na@example.com,20,x\n", `invalid rows: row:1: must have no more than 2 fields`},
+		{"t8", "age,name,name\n", "invalid header: email: required column is missing; name: duplicate column."},
+		{"t9", "", "invalid header: age: required column is missing; email: required column is missing."},
+		{"t10", "email,age\n\"a,20\n", `parse error on line 2, column 7: extraneous or missing " in quoted-field`},
+	}
+	for _, test := range tests {
+		err := newValidator().Validate(strings.NewReader(test.input))
+		if test.err == "" {
+			assert.NoError(t, err, test.tag)
+		} else if assert.Error(t, err, test.tag) {
+			assert.Equal(t, test.err, err.Error(), test.tag)
+		}
+	}
+
+	err := newValidator().AllowUnknownColumns().Validate(strings.NewReader("email,age,name\na@example.com,20,x\n"))
+	assert.NoError(t, err)
+
+	// internal errors stop the validation
+	v := New(Column("a", validation.By(func(interface{}) error {
+		return validation.NewInternalError(errors.New("failed"))
+	})))
+	err = v.Validate(strings.NewReader("a\n1\n2\n"))
+	if assert.Error(t, err) {
+		_, ok := err.(validation.InternalError)
+		assert.True(t, ok)
+	}
+}
+
+func TestValidator_Errors(t *testing.T) {
+	input := "email,age\nb,10\n\"c\nd\",20\na@example.com,x,y\n"
+	err := newValidator().Validate(strings.NewReader(input))
+	if assert.IsType(t, Errors{}, err) {
+		e := err.(Errors)
+		assert.Equal(t, 3, e.Rows)
+		assert.False(t, e.Stopped)
+		if assert.Len(t, e.Cells, 5) {
+			assert.Equal(t, CellError{Row: 1, Line: 2, Column: "email", Err: is.ErrEmail}, e.Cells[0])
+			assert.Equal(t, 3, e.Cells[2].Line, "the line of a cell spanning multiple lines is where it starts")
+			assert.Equal(t, 5, e.Cells[3].Line)
+		}
+		assert.Equal(t, "1: (age: must be no less than 18; email: must be a valid email address.); 2: (email: must be a valid email address.); 3: (_root: must have no more than 2 fields; age: must be an integer.).", e.ToErrors().Error())
+	}
+
+	err = newValidator().Validate(strings.NewReader("email\n"))
+	if assert.IsType(t, Errors{}, err) {
+		assert.Equal(t, "header: (age: required column is missing.).", err.(Errors).ToErrors().Error())
+	}
+}
+
+func TestValidator_MaxErrors(t *testing.T) {
+	input := "email,age\nx,1\ny,2\nz,3\n"
+	err := newValidator().MaxErrors(3).Validate(strings.NewReader(input))
+	if assert.IsType(t, Errors{}, err) {
+		e := err.(Errors)
+		assert.True(t, e.Stopped)
+		assert.Equal(t, 2, e.Rows)
+		assert.Len(t, e.Cells, 3)
+		assert.Equal(t, `invalid rows (stopped after too many errors): row:1, column:"email": must be a valid email address; row:1, column:"age": must be no less than 18; row:2, column:"email": must be a valid email address`, err.Error())
+	}
+}
+
+func TestValidator_OnError(t *testing.T) {
+	input := "email;age\nx;20\na@example.com;20\n"
+	var errs []CellError
+	v := newValidator().OnError(func(ctx context.Context, err CellError) error {
+		errs = append(errs, err)
+		return nil
+	})
+	cr := csv.NewReader(strings.NewReader(input))
+	cr.Comma = ';'
+	err := v.ValidateCSV(context.Background(), cr)
+	if assert.IsType(t, Errors{}, err) {
+		assert.Empty(t, err.(Errors).Cells)
+	}
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, `row:1, column:"email": must be a valid email address`, errs[0].Error())
+	}
+
+	v = newValidator().OnError(func(ctx context.Context, err CellError) error {
+		return errors.New("stop")
+	})
+	assert.EqualError(t, v.Validate(strings.NewReader("email,age\nx,20\n")), "stop")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = newValidator().ValidateWithContext(ctx, strings.NewReader("email,age\nx,20\n"))
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,198 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// deepValidator walks the exported fields of a struct and validates every nested value
+// that implements Validatable or ValidatableWithContext. The values on the path to the value being validated
+// are kept in the validation state, which is carried through nested ValidateStructDeepWithContext calls,
+// and are skipped to break cycles.
+type deepValidator struct {
+	ctx context.Context
+	// listed are the fields that are explicitly specified via Field() and are thus skipped.
+	listed map[visitKey]bool
+}
+
+var deepTypeCache sync.Map
+
+// newDeepValidator creates a deepValidator.
+func newDeepValidator(ctx context.Context, fields []*FieldRules) *deepValidator {
+	d := &deepValidator{ctx: ctx, listed: make(map[visitKey]bool, len(fields))}
+	for _, fr := range fields {
+		if fr.structRule != nil {
+			continue
+		}
+		fv := reflect.ValueOf(fr.fieldPtr)
+		d.listed[visitKey{fv.Pointer(), fv.Type().Elem()}] = true
+	}
+	return d
+}
+
+// validateStruct validates the fields of a struct value and returns the errors keyed by field names.
+func (d *deepValidator) validateStruct(st validationState, v reflect.Value) error {
+	errs := Errors{}
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			// unexported field
+			continue
+		}
+		fv := v.Field(i)
+		if fv.CanAddr() && d.listed[visitKey{fv.UnsafeAddr(), fv.Type()}] {
+			continue
+		}
+		err := d.validateValue(st, fv)
+		if err == nil {
+			continue
+		}
+		if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+			return err
+		}
+		if sf.Anonymous {
+			// merge errors from anonymous struct field
+			if es, ok := err.(Errors); ok {
+				for name, value := range es {
+					errs[name] = value
+				}
+				continue
+			}
+		}
+		errs[getErrorFieldName(&sf)] = err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateValue validates a value if it implements Validatable, either directly or via a pointer
+// receiver when the value is addressable. Otherwise, it descends into pointers, interfaces, structs,
+// slices, arrays and maps looking for nested validatable values.
+func (d *deepValidator) validateValue(st validationState, v reflect.Value) error {
+	if !mayContainValidatable(v.Type()) {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return d.validateValue(st, v.Elem())
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		key := visitKey{v.Pointer(), v.Type()}
+		if v.Kind() == reflect.Ptr {
+			key.typ = v.Type().Elem()
+		}
+		// only the values on the current path are skipped, so that a value shared by several fields
+		// is validated and reported under each of them
+		var ok bool
+		if st, ok = st.visit(key); !ok {
+			// the value is on the current path, i.e., it is part of a cycle
+			return nil
+		}
+	}
+
+	addr := reflect.Value{}
+	if v.CanAddr() {
+		addr = v.Addr()
+	}
+	if ok, err := callValidatable(d.ctx, st, v, addr); ok {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		return d.validateValue(st, v.Elem())
+	case reflect.Struct:
+		return d.validateStruct(st, v)
+	case reflect.Slice, reflect.Array:
+		return d.validateElements(st, v)
+	case reflect.Map:
+		return d.validateMapValues(st, v)
+	}
+	return nil
+}
+
+func (d *deepValidator) validateElements(st validationState, v reflect.Value) error {
+	errs := Errors{}
+	for i := 0; i < v.Len(); i++ {
+		if err := d.validateValue(st, v.Index(i)); err != nil {
+			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+				return err
+			}
+			errs[strconv.Itoa(i)] = err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (d *deepValidator) validateMapValues(st validationState, v reflect.Value) error {
+	errs := Errors{}
+	for _, key := range v.MapKeys() {
+		// copy the map value so that it is addressable and pointer receivers can be used
+		mv := reflect.New(v.Type().Elem()).Elem()
+		mv.Set(v.MapIndex(key))
+		if err := d.validateValue(st, mv); err != nil {
+			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+				return err
+			}
+			errs[getErrorKeyName(key.Interface())] = err
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// mayContainValidatable reports whether a value of the given type may be or may contain
+// a value that implements Validatable or ValidatableWithContext.
+func mayContainValidatable(t reflect.Type) bool {
+	if r, ok := deepTypeCache.Load(t); ok {
+		return r.(bool)
+	}
+	r := typeMayContainValidatable(t, map[reflect.Type]bool{})
+	deepTypeCache.Store(t, r)
+	return r
+}
+
+func typeMayContainValidatable(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if implementsValidatable(t) || t.Kind() != reflect.Ptr && implementsValidatable(reflect.PtrTo(t)) {
+		return true
+	}
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return typeMayContainValidatable(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if (sf.PkgPath == "" || sf.Anonymous) && typeMayContainValidatable(sf.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type deepAddress struct {
+	City string `json:"city"`
+}
+
+func (a deepAddress) Validate() error {
+	return ValidateStruct(&a, Field(&a.City, Required))
+}
+
+type deepPtrItem struct {
+	SKU string `json:"sku"`
+}
+
+func (i *deepPtrItem) Validate() error {
+	return ValidateStruct(i, Field(&i.SKU, Required))
+}
+
+type deepContact struct {
+	Home deepAddress `json:"home"`
+}
+
+type deepOrder struct {
+	ID       string                 `json:"id"`
+	Shipping deepAddress            `json:"shipping"`
+	Billing  *deepAddress           `json:"billing"`
+	Items    []deepPtrItem          `json:"items"`
+	ByCode   map[string]deepPtrItem `json:"by_code"`
+	Contact  deepContact            `json:"contact"`
+	Extra    interface{}            `json:"extra"`
+	Count    int                    `json:"count"`
+	deepContact
+	private deepAddress
+}
+
+type deepNode struct {
+	Name     string      `json:"name"`
+	Parent   *deepNode   `json:"parent"`
+	Children []*deepNode `json:"children"`
+}
+
+func (n *deepNode) ValidateWithContext(ctx context.Context) error {
+	return ValidateStructDeepWithContext(ctx, n, Field(&n.Name, Required))
+}
+
+func TestValidateStructDeep(t *testing.T) {
+	o := deepOrder{
+		Billing: &deepAddress{},
+		Items:   []deepPtrItem{{SKU: "a"}, {}},
+		ByCode:  map[string]deepPtrItem{"x": {}},
+		Extra:   deepAddress{},
+	}
+	err := ValidateStructDeep(&o, Field(&o.ID, Required))
+	assert.EqualError(t, err, "billing: (city: cannot be blank.); by_code: (x: (sku: cannot be blank.).); contact: (home: (city: cannot be blank.).); extra: (city: cannot be blank.); home: (city: cannot be blank.); id: cannot be blank; items: (1: (sku: cannot be blank.).); shipping: (city: cannot be blank.).")
+
+	// ValidateStruct does not validate unlisted fields
+	err = ValidateStruct(&o, Field(&o.ID, Required))
+	assert.EqualError(t, err, "id: cannot be blank.")
+
+	o = deepOrder{
+		ID:       "1",
+		Shipping: deepAddress{City: "a"},
+		Items:    []deepPtrItem{{SKU: "a"}},
+		Contact:  deepContact{Home: deepAddress{City: "b"}},
+	}
+	o.deepContact.Home.City = "c"
+	assert.Nil(t, ValidateStructDeep(&o, Field(&o.ID, Required)))
+	assert.Nil(t, ValidateStructDeepWithContext(context.Background(), &o, Field(&o.ID, Required)))
+
+	// listed fields are not validated again
+	o.Shipping.City = ""
+	err = ValidateStructDeep(&o, Field(&o.Shipping, Skip))
+	assert.Nil(t, err)
+	err = ValidateStructDeep(&o, Field(&o.Shipping.City, Length(2, 5)))
+	assert.EqualError(t, err, "shipping: (city: cannot be blank.).")
+
+	// nil and non-struct pointers
+	var nilOrder *deepOrder
+	assert.Nil(t, ValidateStructDeep(nilOrder))
+	assert.Equal(t, NewInternalError(ErrStructPointer), ValidateStructDeep(o))
+}
+
+func TestValidateStructDeep_Cycle(t *testing.T) {
+	root := &deepNode{Name: "root"}
+	child := &deepNode{Parent: root}
+	grandchild := &deepNode{Name: "grandchild", Parent: child}
+	child.Children = []*deepNode{grandchild, root}
+	root.Children = []*deepNode{child}
+	root.Parent = root
+
+	err := ValidateStructDeepWithContext(context.Background(), root)
+	assert.EqualError(t, err, "children: (0: (name: cannot be blank.).).")
+
+	type loop struct {
+		Self  *loop         `json:"self"`
+		Items []interface{} `json:"items"`
+		Addr  deepAddress   `json:"addr"`
+	}
+	l := &loop{}
+	l.Self = l
+	l.Items = []interface{}{l, l.Items}
+	err = ValidateStructDeep(l)
+	assert.EqualError(t, err, "addr: (city: cannot be blank.).")
+}
+
+func TestValidateStructDeep_SharedValue(t *testing.T) {
+	type contacts struct {
+		Home  *deepAddress            `json:"home"`
+		Work  *deepAddress            `json:"work"`
+		Items []*deepPtrItem          `json:"items"`
+		ByKey map[string]*deepPtrItem `json:"by_key"`
+	}
+	addr := &deepAddress{}
+	item := &deepPtrItem{}
+	c := contacts{Home: addr, Work: addr, Items: []*deepPtrItem{item, item}, ByKey: map[string]*deepPtrItem{"a": item}}
+	err := ValidateStructDeep(&c)
+	assert.EqualError(t, err, "by_key: (a: (sku: cannot be blank.).); home: (city: cannot be blank.); items: (0: (sku: cannot be blank.); 1: (sku: cannot be blank.).); work: (city: cannot be blank.).")
+
+	// the values on the current path are still skipped
+	type node struct {
+		Addr *deepAddress `json:"addr"`
+		Self *node        `json:"self"`
+	}
+	n := &node{Addr: addr}
+	n.Self = n
+	err = ValidateStructDeep(n)
+	assert.EqualError(t, err, "addr: (city: cannot be blank.).")
+}
+
+func TestValidateStructDeep_InternalError(t *testing.T) {
+	type model struct {
+		Value internalErrorValidatable
+	}
+	m := model{}
+	err := ValidateStructDeep(&m)
+	if assert.NotNil(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+	}
+}
+
+type internalErrorValidatable struct{}
+
+func (internalErrorValidatable) Validate() error {
+	return NewInternalError(errors.New("internal"))
+}
+
+func TestMayContainValidatable(t *testing.T) {
+	type plain struct {
+		A int
+		B []string
+		c deepAddress
+	}
+	type recursive struct {
+		Next *recursive
+		Map  map[string]*recursive
+	}
+	assert.False(t, mayContainValidatable(reflect.TypeOf(0)))
+	assert.False(t, mayContainValidatable(reflect.TypeOf(plain{})))
+	assert.False(t, mayContainValidatable(reflect.TypeOf(recursive{})))
+	assert.True(t, mayContainValidatable(reflect.TypeOf(deepAddress{})))
+	assert.True(t, mayContainValidatable(reflect.TypeOf(deepPtrItem{})))
+	assert.True(t, mayContainValidatable(reflect.TypeOf([]deepPtrItem{})))
+	assert.True(t, mayContainValidatable(reflect.TypeOf(deepNode{})))
+	assert.True(t, mayContainValidatable(reflect.TypeOf(deepOrder{})))
+	assert.True(t, mayContainValidatable(reflect.TypeOf([]interface{}{})))
+}
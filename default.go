@@ -0,0 +1,109 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ErrDefaultType is the error that returns when a default value cannot be assigned to a struct field or a map element.
+type ErrDefaultType struct {
+	// Value is the default value.
+	Value interface{}
+	// Type is the type of the struct field or the map element.
+	Type reflect.Type
+}
+
+// Error returns the error string of ErrDefaultType.
+func (e ErrDefaultType) Error() string {
+	return fmt.Sprintf("default value %v of type %T cannot be assigned to %v", e.Value, e.Value, e.Type)
+}
+
+// Default returns a transformation rule that replaces an empty value with the given value.
+// The value is considered empty according to IsEmpty. The rules following it validate the resulting value.
+//
+// When used with Field(), the default value is stored into the struct field if the field is empty.
+// When used with Key(), the default value is stored into the map if the entry is empty or missing,
+// and a missing key is no longer reported as ErrKeyMissing. For example,
+//
+//	validation.Field(&r.PageSize, validation.Default(20), validation.Max(100)),
+//
+// The default value must be assignable or convertible to the type of the struct field or the map element,
+// or to its element type if the field is a pointer. This is checked before any value is validated:
+// ValidateStruct and Map return an ErrDefaultType internal error for a default value of a wrong type.
+func Default(value interface{}) DefaultRule {
+	return DefaultRule{value: value}
+}
+
+// DefaultFunc returns a transformation rule that replaces an empty value with the value returned by
+// the given function. The function is only called when the value is empty. It works like Default,
+// except that the type of the value returned by the function is checked when it is stored.
+func DefaultFunc(f func() interface{}) DefaultRule {
+	return DefaultRule{f: f}
+}
+
+// DefaultRule is a transformation rule that replaces an empty value with a default value.
+type DefaultRule struct {
+	value interface{}
+	f     func() interface{}
+}
+
+// Validate does nothing as the rule only transforms values.
+func (r DefaultRule) Validate(interface{}) error {
+	return nil
+}
+
+func (r DefaultRule) transform(value interface{}) interface{} {
+	if !IsEmpty(value) {
+		return value
+	}
+	if r.f != nil {
+		return r.f()
+	}
+	return r.value
+}
+
+// checkType checks if the default value can be stored into a variable of the given type.
+func (r DefaultRule) checkType(t reflect.Type) error {
+	if r.f != nil {
+		return nil
+	}
+	if _, err := convertValue(r.value, t); err != nil {
+		return ErrDefaultType{Value: r.value, Type: t}
+	}
+	return nil
+}
+
+// checkDefaults checks if the default values among the given rules can be stored into a variable of the given type.
+func checkDefaults(rules []Rule, t reflect.Type) error {
+	for _, rule := range rules {
+		if d, ok := rule.(DefaultRule); ok {
+			if err := d.checkType(t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkFieldDefaults checks if the default values among the given rules can be stored into the field
+// the given pointer points to.
+func checkFieldDefaults(fieldPtr interface{}, rules []Rule) error {
+	if t := reflect.TypeOf(fieldPtr); t != nil && t.Kind() == reflect.Ptr {
+		return checkDefaults(rules, t.Elem())
+	}
+	return nil
+}
+
+// hasDefault checks if any of the given rules is a DefaultRule.
+func hasDefault(rules []Rule) bool {
+	for _, rule := range rules {
+		if _, ok := rule.(DefaultRule); ok {
+			return true
+		}
+	}
+	return false
+}
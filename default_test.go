@@ -0,0 +1,85 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefault_Field(t *testing.T) {
+	type pageSize int
+	type request struct {
+		PageSize pageSize
+		Sort     string
+		Limit    *int
+		Tags     []string
+	}
+
+	calls := 0
+	r := request{Sort: "name"}
+	err := ValidateStruct(&r,
+		Field(&r.PageSize, Default(20), Max(pageSize(10))),
+		Field(&r.Sort, DefaultFunc(func() interface{} { calls++; return "id" }), In("name", "id")),
+		Field(&r.Limit, Default(50)),
+		Field(&r.Tags, Default([]string{"a"}), Length(1, 1)),
+	)
+	assertError(t, "PageSize: must be no greater than 10.", err, "t1")
+	assert.Equal(t, pageSize(20), r.PageSize)
+	assert.Equal(t, "name", r.Sort)
+	assert.Equal(t, 0, calls, "the function is only called for empty values")
+	if assert.NotNil(t, r.Limit) {
+		assert.Equal(t, 50, *r.Limit)
+	}
+	assert.Equal(t, []string{"a"}, r.Tags)
+
+	r.Sort = ""
+	err = ValidateStruct(&r, Field(&r.Sort, DefaultFunc(func() interface{} { calls++; return "id" }), In("name", "id")))
+	assert.NoError(t, err)
+	assert.Equal(t, "id", r.Sort)
+	assert.Equal(t, 1, calls)
+
+	// wrong default types are reported before any field is validated
+	r = request{}
+	err = ValidateStruct(&r,
+		Field(&r.Sort, Default("id")),
+		Field(&r.PageSize, Default("20")),
+	)
+	if assert.Error(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+		assert.Equal(t, ErrDefaultType{Value: "20", Type: reflect.TypeOf(pageSize(0))}, err.(InternalError).InternalError())
+		assert.Equal(t, "default value 20 of type string cannot be assigned to validation.pageSize", err.Error())
+	}
+	assert.Equal(t, "", r.Sort)
+
+	// a wrong type returned by DefaultFunc is reported when the value is stored
+	err = ValidateStruct(&r, Field(&r.PageSize, DefaultFunc(func() interface{} { return "20" })))
+	if assert.Error(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+	}
+}
+
+func TestDefault_Key(t *testing.T) {
+	m := map[string]int{"size": 0, "page": 3}
+	err := Validate(m, Map(
+		Key("size", Default(20), Max(10)),
+		Key("page", Default(1)),
+		Key("offset", Default(5)),
+	))
+	assertError(t, "size: must be no greater than 10.", err, "t1")
+	assert.Equal(t, map[string]int{"size": 20, "page": 3, "offset": 5}, m)
+
+	m2 := map[string]interface{}{}
+	err = Validate(m2, Map(Key("sort", DefaultFunc(func() interface{} { return "id" }), In("id"))))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"sort": "id"}, m2)
+
+	err = Validate(m, Map(Key("size", Default("20"))).AllowExtraKeys())
+	if assert.Error(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+		assert.Equal(t, "default value 20 of type string cannot be assigned to int", err.Error())
+	}
+}
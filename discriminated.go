@@ -0,0 +1,204 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+var (
+	// ErrDiscriminatorField is the error that the discriminator field given to DiscriminatedFields
+	// is not a pointer to a field of the struct being validated.
+	ErrDiscriminatorField = errors.New("the discriminator field must be specified as a pointer to a field of the struct")
+
+	// ErrDiscriminatorUnknown is the error that returns when the discriminator of a value does not select any variant.
+	ErrDiscriminatorUnknown = NewError("validation_discriminator_unknown", "must be a known variant")
+
+	// ErrDiscriminatorType is the error that returns when the type of a value does not select any variant.
+	ErrDiscriminatorType = NewError("validation_discriminator_type", "has an unsupported type {{.type}}")
+)
+
+// DiscriminatedRule is a validation rule that validates a value with one of several rules,
+// selected by a discriminator of the value.
+type DiscriminatedRule struct {
+	key      interface{}
+	variants map[interface{}]Rule
+	byType   bool
+	err      Error
+}
+
+// Discriminated returns a validation rule that validates a map with one of the given rules, selected by the value
+// of the map entry with the given key. It is usually used with Map() rules, one per variant. For example,
+//
+//	validation.Discriminated("type", map[interface{}]validation.Rule{
+//	    "card": validation.Map(validation.Key("number", validation.Required)),
+//	    "bank": validation.Map(validation.Key("iban", validation.Required)),
+//	})
+//
+// The discriminator key does not need to be listed in the selected Map() rule. If the key is missing,
+// ErrKeyMissing is reported on the key. If its value does not select any rule, ErrDiscriminatorUnknown is
+// reported on the key. Otherwise, the errors of the selected rule are returned. Note that the discriminator
+// value must have the same type as the keys of the given map, e.g. a number decoded from JSON is a float64.
+//
+// This rule should only be used for validating maps, or an internal error will be reported.
+// A nil map is considered valid.
+func Discriminated(key interface{}, variants map[interface{}]Rule) DiscriminatedRule {
+	return DiscriminatedRule{key: key, variants: variants, err: ErrDiscriminatorUnknown}
+}
+
+// DiscriminatedByType returns a validation rule that validates a value with one of the given rules, selected by
+// the concrete type of the value. It is usually used with fields and elements of interface types. The keys of the
+// given map are values of the supported types, such as zero values or nil pointers. If there is no rule for
+// the type of a value, a pointer value selects the rule of the type it points to, and any other value selects
+// the rule of the pointer type. As the keys must be comparable, use nil pointers for the types that are not
+// comparable, such as maps and slices. For example,
+//
+//	validation.Field(&p.Method, validation.DiscriminatedByType(map[interface{}]validation.Rule{
+//	    Card{}: cardRule,
+//	    Bank{}: bankRule,
+//	})),
+//
+// If the type does not select any rule, ErrDiscriminatorType is returned. A nil value is considered valid.
+func DiscriminatedByType(variants map[interface{}]Rule) DiscriminatedRule {
+	types := make(map[interface{}]Rule, len(variants))
+	for v, rule := range variants {
+		types[reflect.TypeOf(v)] = rule
+	}
+	return DiscriminatedRule{variants: types, byType: true, err: ErrDiscriminatorType}
+}
+
+// DiscriminatedFields specifies the rules of struct fields that depend on the value of a discriminator field.
+// It can be used with ValidateStruct() alongside Field(). The discriminator field is specified as a pointer to it,
+// and its value selects the list of fields to validate. For example,
+//
+//	validation.ValidateStruct(&p,
+//	    validation.Field(&p.Type, validation.Required),
+//	    validation.DiscriminatedFields(&p.Type, map[interface{}][]*validation.FieldRules{
+//	        "card": {validation.Field(&p.CardNumber, validation.Required)},
+//	        "bank": {validation.Field(&p.IBAN, validation.Required)},
+//	    }),
+//	)
+//
+// If the value of the discriminator field does not select any list, ErrDiscriminatorUnknown is reported on
+// the discriminator field, unless the field is empty. Use the Required rule to make sure it is provided.
+func DiscriminatedFields(fieldPtr interface{}, variants map[interface{}][]*FieldRules) *FieldRules {
+	return &FieldRules{structRule: func(ctx context.Context, st validationState, value reflect.Value) error {
+		fv := reflect.ValueOf(fieldPtr)
+		if fv.Kind() != reflect.Ptr {
+			return NewInternalError(ErrDiscriminatorField)
+		}
+		fm, _ := findStructFieldCached(value, fv)
+		if fm == nil {
+			return NewInternalError(ErrDiscriminatorField)
+		}
+		d, isNil := Indirect(fv.Elem().Interface())
+		if isNil || IsEmpty(d) {
+			return nil
+		}
+		fields, ok := lookupVariant(variants, d)
+		if !ok {
+			errs := Errors{}
+			addFieldError(errs, fm, ErrDiscriminatorUnknown.SetParams(map[string]interface{}{"value": d}))
+			return errs
+		}
+		for _, fr := range fields {
+			if fr.err != nil {
+				return NewInternalError(fr.err)
+			}
+		}
+		return validateStructFields(ctx, st, value, false, fields)
+	}}
+}
+
+// Validate checks if the given value is valid or not.
+func (r DiscriminatedRule) Validate(value interface{}) error {
+	return r.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r DiscriminatedRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	return r.validateWithState(ctx, stateOf(ctx), value)
+}
+
+func (r DiscriminatedRule) validateWithState(ctx context.Context, st validationState, value interface{}) error {
+	if r.byType {
+		return r.validateType(ctx, st, value)
+	}
+
+	m := reflect.ValueOf(value)
+	if m.Kind() == reflect.Ptr {
+		m = m.Elem()
+	}
+	if m.Kind() != reflect.Map {
+		// must be a map
+		return NewInternalError(ErrNotMap)
+	}
+	if m.IsNil() {
+		// treat a nil map as valid
+		return nil
+	}
+
+	name := getErrorKeyName(r.key)
+	kv := reflect.ValueOf(r.key)
+	if !m.Type().Key().AssignableTo(kv.Type()) {
+		return Errors{name: ErrKeyWrongType}
+	}
+	dv := m.MapIndex(kv)
+	if !dv.IsValid() {
+		return Errors{name: ErrKeyMissing}
+	}
+	d, _ := Indirect(dv.Interface())
+	rule, ok := lookupVariant(r.variants, d)
+	if !ok {
+		return Errors{name: r.err.SetParams(map[string]interface{}{"value": d})}
+	}
+	if mr, ok := rule.(MapRule); ok {
+		rule = mr.withKey(r.key)
+	}
+	return validate(ctx, st, value, reflect.Value{}, []Rule{rule})
+}
+
+// validateType validates a value with the rule selected by the type of the value.
+func (r DiscriminatedRule) validateType(ctx context.Context, st validationState, value interface{}) error {
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() || (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return nil
+	}
+	rule, ok := r.variants[rv.Type()]
+	if !ok && rv.Kind() == reflect.Ptr {
+		rule, ok = r.variants[rv.Type().Elem()]
+	} else if !ok {
+		rule, ok = r.variants[reflect.PtrTo(rv.Type())]
+	}
+	if !ok {
+		return r.err.SetParams(map[string]interface{}{"type": rv.Type().String()})
+	}
+	return validate(ctx, st, value, reflect.Value{}, []Rule{rule})
+}
+
+// Error sets the error message that is used when the discriminator does not select any variant.
+func (r DiscriminatedRule) Error(message string) DiscriminatedRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct that is used when the discriminator does not select any variant.
+func (r DiscriminatedRule) ErrorObject(err Error) DiscriminatedRule {
+	r.err = err
+	return r
+}
+
+// lookupVariant looks up the variant selected by a discriminator value. Values of types that are not
+// comparable do not select any variant.
+func lookupVariant[T any](variants map[interface{}]T, d interface{}) (T, bool) {
+	if t := reflect.TypeOf(d); t == nil || !t.Comparable() {
+		var zero T
+		return zero, false
+	}
+	v, ok := variants[d]
+	return v, ok
+}
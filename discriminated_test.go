@@ -0,0 +1,129 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cardPayment struct {
+	Number string `json:"number"`
+}
+
+type bankPayment struct {
+	IBAN string `json:"iban"`
+}
+
+type paymentMethod interface{}
+
+type payment struct {
+	Type   string        `json:"type"`
+	Number string        `json:"number"`
+	IBAN   string        `json:"iban"`
+	Method paymentMethod `json:"method"`
+}
+
+func TestDiscriminated(t *testing.T) {
+	rule := Discriminated("type", map[interface{}]Rule{
+		"card": Map(Key("number", Required, Length(16, 16))),
+		"bank": Map(Key("type"), Key("iban", Required)),
+		"cash": By(func(value interface{}) error { return errors.New("not accepted") }),
+	})
+	tests := []struct {
+		tag   string
+		value interface{}
+		err   string
+	}{
+		{"t1", map[string]interface{}{"type": "card", "number": "1234567812345678"}, ""},
+		{"t2", map[string]interface{}{"type": "card", "number": "123"}, "number: the length must be exactly 16."},
+		{"t3", map[string]interface{}{"type": "card", "iban": "x"}, "iban: key not expected; number: required key is missing."},
+		{"t4", map[string]interface{}{"type": "bank", "iban": "x"}, ""},
+		{"t5", map[string]interface{}{"type": "bank"}, "iban: required key is missing."},
+		{"t6", map[string]interface{}{"type": "cash"}, "not accepted"},
+		{"t7", map[string]interface{}{"type": "crypto"}, "type: must be a known variant."},
+		{"t8", map[string]interface{}{"type": []string{"card"}}, "type: must be a known variant."},
+		{"t9", map[string]interface{}{"number": "x"}, "type: required key is missing."},
+		{"t10", map[int]interface{}{1: "x"}, "type: key not the correct type."},
+		{"t11", map[string]interface{}(nil), ""},
+		{"t12", &map[string]interface{}{"type": "bank", "iban": "x"}, ""},
+		{"t13", "card", "only a map can be validated"},
+	}
+	for _, test := range tests {
+		err := Validate(test.value, rule)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	err := Validate(map[string]interface{}{"type": "crypto"}, rule.Error("is not supported"))
+	assertError(t, "type: is not supported.", err, "t14")
+
+	// nested in a map
+	err = ValidateWithContext(context.Background(), map[string]interface{}{
+		"payment": map[string]interface{}{"type": "bank", "iban": ""},
+	}, Map(Key("payment", rule)))
+	assertError(t, "payment: (iban: cannot be blank.).", err, "t15")
+}
+
+func TestDiscriminatedByType(t *testing.T) {
+	rule := DiscriminatedByType(map[interface{}]Rule{
+		cardPayment{}:             By(func(value interface{}) error { return nil }),
+		(*bankPayment)(nil):       By(func(value interface{}) error { return errors.New("bank") }),
+		(*map[string]string)(nil): Map(Key("a", Required)),
+	})
+	tests := []struct {
+		tag   string
+		value interface{}
+		err   string
+	}{
+		{"t1", cardPayment{}, ""},
+		{"t2", &cardPayment{}, ""},
+		{"t3", &bankPayment{}, "bank"},
+		{"t4", bankPayment{}, "bank"},
+		{"t5", nil, ""},
+		{"t6", (*bankPayment)(nil), ""},
+		{"t7", map[string]string{}, "a: required key is missing."},
+		{"t8", 1, "has an unsupported type int"},
+	}
+	for _, test := range tests {
+		err := Validate(test.value, rule)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	p := payment{Method: &bankPayment{}}
+	err := ValidateStruct(&p, Field(&p.Method, rule))
+	assertError(t, "method: bank.", err, "t9")
+}
+
+func TestDiscriminatedFields(t *testing.T) {
+	p := payment{}
+	rules := func() []*FieldRules {
+		return []*FieldRules{
+			Field(&p.Type, Required),
+			DiscriminatedFields(&p.Type, map[interface{}][]*FieldRules{
+				"card": {Field(&p.Number, Required), Field(&p.IBAN, Empty)},
+				"bank": {Field(&p.IBAN, Required)},
+			}),
+		}
+	}
+	tests := []struct {
+		tag   string
+		value payment
+		err   string
+	}{
+		{"t1", payment{Type: "card", Number: "1"}, ""},
+		{"t2", payment{Type: "card", IBAN: "x"}, "iban: must be blank; number: cannot be blank."},
+		{"t3", payment{Type: "bank"}, "iban: cannot be blank."},
+		{"t4", payment{Type: "cash"}, "type: must be a known variant."},
+		{"t5", payment{}, "type: cannot be blank."},
+	}
+	for _, test := range tests {
+		p = test.value
+		err := ValidateStruct(&p, rules()...)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	other := payment{}
+	err := ValidateStruct(&p, DiscriminatedFields(&other.Type, nil))
+	assert.Equal(t, NewInternalError(ErrDiscriminatorField), err)
+}
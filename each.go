@@ -9,18 +9,28 @@ import (
 	"errors"
 	"reflect"
 	"strconv"
+	"sync"
 )
 
-// Each returns a validation rule that loops through an iterable (map, slice or array)
+// errNotIterable is the error returned by Each and the related rules for a value that is not iterable.
+var errNotIterable = errors.New("must be an iterable (map, slice or array)")
+
+// Each returns a validation rule that loops through an iterable (map, slice, array or iterator)
 // and validates each value inside with the provided rules.
 // An empty iterable is considered valid. Use the Required rule to make sure the iterable is not empty.
+//
+// Iterators are supported as well: functions of the iter.Seq and iter.Seq2 types, values with an All()
+// method returning such a function.
+// Their values are validated lazily as they are produced, and keyed by their positions, or by the keys
+// produced by iter.Seq2 functions.
 func Each(rules ...Rule) EachRule {
 	return EachRule{
 		rules: rules,
 	}
 }
 
-// EachRule is a validation rule that validates elements in a map/slice/array using the specified list of rules.
+// EachRule is a validation rule that validates elements in a map, a slice, an array or an iterator
+// using the specified list of rules.
 type EachRule struct {
 	rules []Rule
 }
@@ -32,44 +42,151 @@ func (r EachRule) Validate(value interface{}) error {
 
 // ValidateWithContext loops through the given iterable and calls the Ozzo ValidateWithContext() method for each value.
 func (r EachRule) ValidateWithContext(ctx context.Context, value interface{}) error {
-	errs := Errors{}
+	return r.validateWithState(ctx, stateOf(ctx), value)
+}
 
+func (r EachRule) validateWithState(ctx context.Context, st validationState, value interface{}) error {
+	if ok, err := validateEach(ctx, st, value, eachValidator{rules: r.rules}); !ok {
+		return errNotIterable
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// eachValidator validates the elements of an iterable. See validateEach().
+type eachValidator struct {
+	// rules are the rules for all elements, used if rulesFunc is nil.
+	rules []Rule
+	// rulesFunc returns the rules for an element given its key, its index and its value. The key is only
+	// valid for the entries of a map and the values produced by an iter.Seq2 function.
+	rulesFunc func(ctx context.Context, key reflect.Value, index int, value interface{}) []Rule
+	// untilFirstError indicates the validation stops at the first invalid element.
+	untilFirstError bool
+
+	ctx         context.Context
+	st          validationState
+	errs        Errors
+	internalErr error
+	// isMap indicates the elements are the entries of a map, whose errors are keyed by getIterableString().
+	isMap bool
+	// elemType is the type of the last validated element, and addressable indicates whether the elements
+	// of that type are validated through pointers to them.
+	elemType    reflect.Type
+	addressable bool
+}
+
+// validateEach validates the elements of a map, a slice, an array or an iterator (see iterate()) with the rules
+// of the given validator, at the nesting level following the one of the given state. The errors are returned as
+// Errors keyed by the map keys, the keys produced by iter.Seq2 functions, or the element indexes. The boolean
+// result is false if the value is not iterable.
+func validateEach(ctx context.Context, st validationState, value interface{}, ev eachValidator) (bool, error) {
+	ev.ctx, ev.st = ctx, st
+	ev.errs = Errors{}
+	iterable := true
 	v := reflect.ValueOf(value)
 	switch v.Kind() {
 	case reflect.Map:
-		for _, k := range v.MapKeys() {
-			val := getIterableInterface(v.MapIndex(k))
-			var err error
-			if ctx == nil {
-				err = Validate(val, r.rules...)
-			} else {
-				err = ValidateWithContext(ctx, val, r.rules...)
-			}
-			if err != nil {
-				errs[getIterableString(k)] = err
+		ev.isMap = true
+		for i, k := range v.MapKeys() {
+			if !ev.validateElement(k, i, v.MapIndex(k)) {
+				break
 			}
 		}
 	case reflect.Slice, reflect.Array:
-		for i := 0; i < v.Len(); i++ {
-			val := getIterableInterface(v.Index(i))
-			var err error
-			if ctx == nil {
-				err = Validate(val, r.rules...)
-			} else {
-				err = ValidateWithContext(ctx, val, r.rules...)
-			}
-			if err != nil {
-				errs[strconv.Itoa(i)] = err
+		for i, n := 0, v.Len(); i < n; i++ {
+			if !ev.validateElement(reflect.Value{}, i, v.Index(i)) {
+				break
 			}
 		}
 	default:
-		return errors.New("must be an iterable (map, slice or array)")
+		// a copy is passed to iterate, so that only the validation of iterators moves the validator to the heap
+		iv := ev
+		iterable = iterate(v, iv.validateElement)
+		ev = iv
+	}
+	if !iterable {
+		return false, nil
 	}
 
-	if len(errs) > 0 {
-		return errs
+	if ev.internalErr != nil {
+		return true, ev.internalErr
 	}
-	return nil
+	if len(ev.errs) > 0 {
+		return true, ev.errs
+	}
+	return true, nil
+}
+
+// validateElement validates an element of an iterable. It returns false if the iteration should stop.
+func (ev *eachValidator) validateElement(key reflect.Value, index int, value reflect.Value) bool {
+	if t := value.Type(); t != ev.elemType {
+		ev.elemType, ev.addressable = t, ptrValidatable(t)
+	}
+	val := getIterableInterface(value)
+	rules := ev.rules
+	if ev.rulesFunc != nil {
+		rules = ev.rulesFunc(ev.ctx, key, index, val)
+	}
+	var ptr reflect.Value
+	if ev.addressable {
+		if value.CanAddr() && hasTransformer(rules) {
+			// the transformed values are not stored back into the iterable, so a copy of the element is used
+			ptr = reflect.New(value.Type())
+			ptr.Elem().Set(value)
+		} else {
+			ptr = addressOf(value)
+		}
+	}
+
+	err := validate(ev.ctx, ev.st, val, ptr, rules)
+	if err == nil {
+		return true
+	}
+	// a nil key is reported as an empty one
+	name := ""
+	if !key.IsValid() {
+		name = strconv.Itoa(index)
+	} else if ev.isMap {
+		name = getIterableString(key)
+	} else if k := getIterableInterface(key); k != nil {
+		name = getErrorKeyName(k)
+	}
+	if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+		ev.internalErr = prependPath(err, name)
+		return false
+	}
+	ev.errs[name] = err
+	return !ev.untilFirstError
+}
+
+// elementPointer returns a pointer to an element of an iterable, which is used to call the element's
+// validation method declared with a pointer receiver. If the element is not addressable, a pointer to
+// a copy of it is returned. An invalid value is returned if the element type has no such method.
+func elementPointer(value reflect.Value) reflect.Value {
+	if !ptrValidatable(value.Type()) {
+		return reflect.Value{}
+	}
+	return addressOf(value)
+}
+
+// ptrValidatableTypes caches whether the pointers to the values of a type implement Validatable
+// or ValidatableWithContext.
+var ptrValidatableTypes sync.Map // reflect.Type => bool
+
+// ptrValidatable checks if the pointers to the values of the given type implement Validatable
+// or ValidatableWithContext.
+func ptrValidatable(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		// pointers to pointers and interfaces have no methods
+		return false
+	}
+	if r, ok := ptrValidatableTypes.Load(t); ok {
+		return r.(bool)
+	}
+	r := implementsValidatable(reflect.PtrTo(t))
+	ptrValidatableTypes.Store(t, r)
+	return r
 }
 
 func getIterableInterface(value reflect.Value) interface{} {
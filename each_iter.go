@@ -0,0 +1,77 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"reflect"
+)
+
+// iterate calls f for each value produced by an iterator, until f returns false. The supported iterators are
+// functions of the iter.Seq and iter.Seq2 types (or any function type with the same signature), values with an
+// All() method returning such a function. The key is only valid for the values produced by iter.Seq2 functions,
+// and the index is the position of the value. A nil function produces no values.
+// The boolean result is false if the value is not an iterator.
+func iterate(v reflect.Value, f func(key reflect.Value, index int, value reflect.Value) bool) bool {
+	if !v.IsValid() {
+		return false
+	}
+	n := seqArity(v.Type())
+	if n == 0 {
+		if v = allMethod(v); !v.IsValid() {
+			return false
+		}
+		n = seqArity(v.Type())
+	}
+	if v.IsNil() {
+		return true
+	}
+
+	index := 0
+	yield := reflect.MakeFunc(v.Type().In(0), func(args []reflect.Value) []reflect.Value {
+		var ok bool
+		if n == 2 {
+			ok = f(args[0], index, args[1])
+		} else {
+			ok = f(reflect.Value{}, index, args[0])
+		}
+		index++
+		return []reflect.Value{reflect.ValueOf(ok)}
+	})
+	v.Call([]reflect.Value{yield})
+	return true
+}
+
+// seqArity returns 1 if the given type has the signature of iter.Seq, 2 if it has the signature of iter.Seq2,
+// and 0 otherwise.
+func seqArity(t reflect.Type) int {
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 0 || t.IsVariadic() {
+		return 0
+	}
+	y := t.In(0)
+	if y.Kind() != reflect.Func || y.NumOut() != 1 || y.Out(0).Kind() != reflect.Bool || y.IsVariadic() {
+		return 0
+	}
+	if n := y.NumIn(); n == 1 || n == 2 {
+		return n
+	}
+	return 0
+}
+
+// allMethod returns the iterator returned by the All() method of a value, such as a collection or a database
+// cursor, or an invalid value if the value has no such method. A method with a pointer receiver is called
+// on a copy of the value. A nil pointer has no such method.
+func allMethod(v reflect.Value) reflect.Value {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return reflect.Value{}
+	}
+	m := v.MethodByName("All")
+	if !m.IsValid() && v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface {
+		m = addressOf(v).MethodByName("All")
+	}
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 || seqArity(m.Type().Out(0)) == 0 {
+		return reflect.Value{}
+	}
+	return m.Call(nil)[0]
+}
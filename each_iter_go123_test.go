@@ -0,0 +1,19 @@
+//go:build go1.23
+
+package validation
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestEach_IterSeq(t *testing.T) {
+	var seq iter.Seq[string] = slices.Values([]string{"a", ""})
+	assertError(t, "1: cannot be blank.", Validate(seq, Each(Required)), "t1")
+
+	var seq2 iter.Seq2[string, int] = maps.All(map[string]int{"x": 1, "y": 0})
+	assertError(t, "y: cannot be blank.", Validate(seq2, Each(Required)), "t2")
+	assertError(t, "y: cannot be blank.", Validate(seq2, EachUntilFirstError(Required)), "t3")
+}
@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// seqOf returns a function with the signature of iter.Seq that yields the given values
+// and records how many values are consumed.
+func seqOf(consumed *int, values ...string) func(yield func(string) bool) {
+	return func(yield func(string) bool) {
+		for _, v := range values {
+			*consumed++
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+type seq2 func(yield func(int, string) bool)
+
+type cursor struct {
+	rows []string
+}
+
+func (c *cursor) All() func(yield func(string) bool) {
+	return func(yield func(string) bool) {
+		for _, row := range c.rows {
+			if !yield(row) {
+				return
+			}
+		}
+	}
+}
+
+func TestEach_Iterator(t *testing.T) {
+	var n int
+	pairs := seq2(func(yield func(int, string) bool) {
+		_ = yield(10, "a") && yield(20, "") && yield(30, "")
+	})
+	var nilSeq func(yield func(string) bool)
+	ch := make(chan string, 3)
+	ch <- "a"
+	ch <- ""
+
+	tests := []struct {
+		tag   string
+		value interface{}
+		err   string
+	}{
+		{"t1", seqOf(&n, "a", "", "b", ""), "1: cannot be blank; 3: cannot be blank."},
+		{"t2", seqOf(&n), ""},
+		{"t3", nilSeq, ""},
+		{"t4", pairs, "20: cannot be blank; 30: cannot be blank."},
+		{"t5", &cursor{rows: []string{"", "a"}}, "0: cannot be blank."},
+		{"t6", cursor{rows: []string{"a", ""}}, "1: cannot be blank."},
+		{"t7", ch, "must be an iterable (map, slice or array)"},
+		{"t8", (*cursor)(nil), "must be an iterable (map, slice or array)"},
+		{"t9", func(string) bool { return true }, "must be an iterable (map, slice or array)"},
+	}
+	for _, test := range tests {
+		err := Validate(test.value, Each(Required))
+		assertError(t, test.err, err, test.tag)
+	}
+	assert.Equal(t, 2, len(ch), "channels are not received from")
+
+	// the values are validated while they are produced
+	n = 0
+	err := Validate(seqOf(&n, "a", "", "b"), Each(By(func(value interface{}) error {
+		if value == "b" {
+			return NewInternalError(errors.New("failed"))
+		}
+		return nil
+	})))
+	if assert.Error(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+	}
+	assert.Equal(t, 3, n)
+
+	// Validatable values are validated by their validation methods
+	models := func(yield func(Model3) bool) {
+		_ = yield(Model3{A: "abc"}) && yield(Model3{A: "xyz"})
+	}
+	assertError(t, "1: (A: error abc.).", Validate(models, Each()), "t10")
+	assertError(t, "1: (A: error abc.).", ValidateWithContext(context.Background(), models, Each()), "t11")
+}
+
+func TestEachUntilFirstError_Iterator(t *testing.T) {
+	var n int
+	err := Validate(seqOf(&n, "a", "", "b", ""), EachUntilFirstError(Required))
+	assertError(t, "1: cannot be blank.", err, "t1")
+	assert.Equal(t, 2, n, "the iteration stops at the first error")
+
+	n = 0
+	assert.NoError(t, Validate(seqOf(&n, "a", "b"), EachUntilFirstError(Required)))
+	assert.Equal(t, 2, n)
+
+	pairs := seq2(func(yield func(int, string) bool) {
+		_ = yield(1, "") && yield(2, "")
+	})
+	assertError(t, "1: cannot be blank.", Validate(pairs, EachUntilFirstError(Required)), "t2")
+}
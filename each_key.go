@@ -0,0 +1,102 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"reflect"
+)
+
+// KeyError is the error of a map key that fails validation. It is reported in Errors under the key,
+// so that it can be told apart from the errors of the map values.
+type KeyError struct {
+	// Err is the validation error of the key.
+	Err error
+}
+
+// Error returns the error string of KeyError.
+func (e KeyError) Error() string {
+	return "invalid key: " + e.Err.Error()
+}
+
+// Unwrap returns the validation error of the key.
+func (e KeyError) Unwrap() error {
+	return e.Err
+}
+
+// EachKey returns a validation rule that loops through a map and validates each key with the given rules.
+// The error of a key is reported as a KeyError under the key. For example,
+//
+//	validation.Validate(labels, validation.EachKey(validation.Match(slugRegex)))
+//
+// This rule should only be used for validating maps, or an internal error will be reported.
+// A nil map is considered valid.
+func EachKey(rules ...Rule) EachEntryRule {
+	return EachEntryRule{keyRules: rules}
+}
+
+// EachEntry returns a validation rule that loops through a map and validates each key with keyRules
+// and each value with valueRules. The error of a key is reported as a KeyError under the key, in which
+// case the value is not validated. The error of a value is reported under the key as is.
+//
+// This rule should only be used for validating maps, or an internal error will be reported.
+// A nil map is considered valid.
+func EachEntry(keyRules, valueRules []Rule) EachEntryRule {
+	return EachEntryRule{keyRules: keyRules, valueRules: valueRules}
+}
+
+// EachEntryRule is a validation rule that validates the keys and values of a map using the specified lists of rules.
+type EachEntryRule struct {
+	keyRules   []Rule
+	valueRules []Rule
+}
+
+// Validate checks if the given value is valid or not.
+func (r EachEntryRule) Validate(value interface{}) error {
+	return r.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r EachEntryRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	return r.validateWithState(ctx, stateOf(ctx), value)
+}
+
+func (r EachEntryRule) validateWithState(ctx context.Context, st validationState, value interface{}) error {
+	m := reflect.ValueOf(value)
+	if m.Kind() == reflect.Ptr {
+		m = m.Elem()
+	}
+	if m.Kind() != reflect.Map {
+		// must be a map
+		return NewInternalError(ErrNotMap)
+	}
+	if m.IsNil() {
+		// treat a nil map as valid
+		return nil
+	}
+
+	errs := Errors{}
+	for _, k := range m.MapKeys() {
+		err := validate(ctx, st, getIterableInterface(k), reflect.Value{}, r.keyRules)
+		if err == nil {
+			if v := m.MapIndex(k); len(r.valueRules) > 0 {
+				err = validate(ctx, st, getIterableInterface(v), elementPointer(v), r.valueRules)
+			}
+		} else if ie, ok := err.(InternalError); !ok || ie.InternalError() == nil {
+			err = KeyError{Err: err}
+		}
+		if err != nil {
+			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+				return prependPath(err, getErrorKeyName(k.Interface()))
+			}
+			errs[getErrorKeyName(k.Interface())] = err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var slugRegex = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+func TestEachKey(t *testing.T) {
+	tests := []struct {
+		tag   string
+		value interface{}
+		err   string
+	}{
+		{"t1", map[string]int{"a-1": 1, "b": 0}, ""},
+		{"t2", map[string]int{"a-1": 1, "B_2": 0}, "B_2: invalid key: must be in a valid format."},
+		{"t3", map[string]int{"": 1}, ": invalid key: cannot be blank."},
+		{"t4", map[int]string{1: "a", 10: "b"}, "10: invalid key: must be no greater than 5."},
+		{"t5", &map[string]int{"B": 1}, "B: invalid key: must be in a valid format."},
+		{"t6", map[string]int(nil), ""},
+		{"t7", []string{"a"}, "only a map can be validated"},
+	}
+	for _, test := range tests {
+		var err error
+		if _, ok := test.value.(map[int]string); ok {
+			err = Validate(test.value, EachKey(Max(5)))
+		} else {
+			err = Validate(test.value, EachKey(Required, Match(slugRegex)))
+		}
+		assertError(t, test.err, err, test.tag)
+	}
+
+	err := Validate(map[string]int{"B": 1}, EachKey(Match(slugRegex)))
+	if assert.IsType(t, Errors{}, err) {
+		var ke KeyError
+		assert.True(t, errors.As(err.(Errors)["B"], &ke))
+		assert.Equal(t, ErrMatchInvalid, ke.Err)
+	}
+}
+
+func TestEachEntry(t *testing.T) {
+	rule := EachEntry([]Rule{Match(slugRegex)}, []Rule{Required, Length(0, 3)})
+	tests := []struct {
+		tag   string
+		value interface{}
+		err   string
+	}{
+		{"t1", map[string]string{"a": "abc", "b": "x"}, ""},
+		{"t2", map[string]string{"a": "abcd", "b": ""}, "a: the length must be no more than 3; b: cannot be blank."},
+		{"t3", map[string]string{"A": "abcd"}, "A: invalid key: must be in a valid format."},
+		{"t4", map[string]interface{}{"a": nil}, "a: cannot be blank."},
+	}
+	for _, test := range tests {
+		err := Validate(test.value, rule)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	// an internal error of a key rule is returned as is
+	err := Validate(map[string]string{"a": "b"}, EachKey(By(func(value interface{}) error {
+		return NewInternalError(errors.New("internal"))
+	})))
+	if assert.Error(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+	}
+}
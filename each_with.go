@@ -0,0 +1,109 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+)
+
+// EachWithRule is a validation rule that validates the elements in a map, a slice, an array or an iterator
+// using the rules returned for each element by a function. See EachWithIndex() and EachWithKey().
+type EachWithRule struct {
+	rules   func(ctx context.Context, key, value interface{}) []Rule
+	byIndex bool
+}
+
+// EachWithIndex returns a validation rule that loops through a slice or an array and validates each element
+// with the rules returned by the given function for the index and the value of the element. For example,
+//
+//	validation.Field(&csv.Rows, validation.EachWithIndex(func(i int, value interface{}) []validation.Rule {
+//	    if i == 0 {
+//	        return []validation.Rule{validation.By(checkHeader)}
+//	    }
+//	    return []validation.Rule{validation.By(checkRow)}
+//	})),
+//
+// The errors are keyed by the indexes of the elements in the same way as Each.
+// An empty iterable is considered valid. Use the Required rule to make sure the iterable is not empty.
+func EachWithIndex(f func(i int, value interface{}) []Rule) EachWithRule {
+	return EachWithIndexWithContext(func(_ context.Context, i int, value interface{}) []Rule {
+		return f(i, value)
+	})
+}
+
+// EachWithIndexWithContext is the context-aware version of EachWithIndex. The context passed to the function
+// is the one the rule is validated with.
+func EachWithIndexWithContext(f func(ctx context.Context, i int, value interface{}) []Rule) EachWithRule {
+	return EachWithRule{
+		rules: func(ctx context.Context, key, value interface{}) []Rule {
+			return f(ctx, key.(int), value)
+		},
+		byIndex: true,
+	}
+}
+
+// EachWithKey returns a validation rule that loops through an iterable (map, slice, array or iterator)
+// and validates each value with the rules returned by the given function for the key and the value. The key
+// of a slice or an array element is its index as an int. For example,
+//
+//	validation.Field(&c.Servers, validation.EachWithKey(func(key, value interface{}) []validation.Rule {
+//	    return []validation.Rule{validation.By(func(value interface{}) error {
+//	        if value.(Server).Name != key {
+//	            return errors.New("must have the same name as its key")
+//	        }
+//	        return nil
+//	    })}
+//	})),
+//
+// The errors are keyed by the map keys or element indexes in the same way as Each. Iterators are supported
+// in the same way as Each as well: the key of a value produced by an iter.Seq2 function is the key produced
+// with it, and the key of any other value is its position as an int.
+// An empty iterable is considered valid. Use the Required rule to make sure the iterable is not empty.
+func EachWithKey(f func(key, value interface{}) []Rule) EachWithRule {
+	return EachWithKeyWithContext(func(_ context.Context, key, value interface{}) []Rule {
+		return f(key, value)
+	})
+}
+
+// EachWithKeyWithContext is the context-aware version of EachWithKey. The context passed to the function
+// is the one the rule is validated with.
+func EachWithKeyWithContext(f func(ctx context.Context, key, value interface{}) []Rule) EachWithRule {
+	return EachWithRule{rules: f}
+}
+
+// Validate loops through the given iterable and validates each value with the rules returned for it.
+func (r EachWithRule) Validate(value interface{}) error {
+	return r.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext loops through the given iterable and validates each value with the rules returned for it.
+func (r EachWithRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	return r.validateWithState(ctx, stateOf(ctx), value)
+}
+
+func (r EachWithRule) validateWithState(ctx context.Context, st validationState, value interface{}) error {
+	if r.byIndex {
+		if k := reflect.ValueOf(value).Kind(); k != reflect.Slice && k != reflect.Array {
+			return errors.New("must be an iterable with indexes (slice or array)")
+		}
+	}
+	if ok, err := validateEach(ctx, st, value, eachValidator{rulesFunc: r.elementRules}); !ok {
+		return errNotIterable
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// elementRules returns the rules for an element given its key, which is only valid for map entries and
+// the values produced by iter.Seq2 functions, and its index.
+func (r EachWithRule) elementRules(ctx context.Context, key reflect.Value, index int, value interface{}) []Rule {
+	if key.IsValid() {
+		return r.rules(ctx, key.Interface(), value)
+	}
+	return r.rules(ctx, index, value)
+}
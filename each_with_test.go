@@ -0,0 +1,112 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEachWithIndex(t *testing.T) {
+	values := []int{0, 3, 2, 5}
+	rule := EachWithIndex(func(i int, value interface{}) []Rule {
+		if i == 0 {
+			return []Rule{Empty}
+		}
+		return []Rule{Min(values[i-1])}
+	})
+
+	tests := []struct {
+		tag   string
+		value interface{}
+		err   string
+	}{
+		{"t1", values, "2: must be no less than 3."},
+		{"t2", [2]int{0, 1}, ""},
+		{"t3", []int{}, ""},
+		{"t4", []int{1}, "0: must be blank."},
+		{"t5", map[string]int{"a": 1}, "must be an iterable with indexes (slice or array)"},
+		{"t6", nil, "must be an iterable with indexes (slice or array)"},
+	}
+	for _, test := range tests {
+		err := rule.Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	// nil rules validate the elements by their own validation methods only
+	err := Validate([]Model3{{}, {}}, EachWithIndex(func(i int, value interface{}) []Rule {
+		if i == 0 {
+			return []Rule{Skip}
+		}
+		return nil
+	}))
+	assertError(t, "1: (A: error abc.).", err, "t7")
+
+	// internal errors are returned as is
+	err = Validate([]int{1}, EachWithIndex(func(i int, value interface{}) []Rule {
+		return []Rule{By(func(interface{}) error { return NewInternalError(errors.New("failed")) })}
+	}))
+	if assert.Error(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+	}
+}
+
+func TestEachWithKey(t *testing.T) {
+	sameAsKey := func(key, value interface{}) []Rule {
+		return []Rule{By(func(value interface{}) error {
+			if value != key {
+				return errors.New("must be the same as its key")
+			}
+			return nil
+		})}
+	}
+
+	tests := []struct {
+		tag   string
+		value interface{}
+		err   string
+	}{
+		{"t1", map[string]string{"a": "a", "b": "c", "d": "e"}, "b: must be the same as its key; d: must be the same as its key."},
+		{"t2", map[int]int{1: 1, 2: 2}, ""},
+		{"t3", []int{0, 1, 3}, "2: must be the same as its key."},
+		{"t4", map[string]string{}, ""},
+		{"t5", 1, "must be an iterable (map, slice or array)"},
+		{"t6", func(yield func(int) bool) { _ = yield(0) && yield(2) }, "1: must be the same as its key."},
+	}
+	for _, test := range tests {
+		err := Validate(test.value, EachWithKey(sameAsKey))
+		assertError(t, test.err, err, test.tag)
+	}
+
+	// the errors are reported under the field
+	s := struct{ M map[string]string }{map[string]string{"a": "b"}}
+	err := ValidateStruct(&s, Field(&s.M, EachWithKey(sameAsKey)))
+	assertError(t, "M: (a: must be the same as its key.).", err, "t6")
+}
+
+func TestEachWithContextVariants(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contains, "abc")
+	var indexes []int
+	rule := EachWithIndexWithContext(func(ctx context.Context, i int, value interface{}) []Rule {
+		indexes = append(indexes, i)
+		return []Rule{In(ctx.Value(contains))}
+	})
+	err := ValidateWithContext(ctx, []string{"abc", "xyz"}, rule)
+	assertError(t, "1: must be a valid value.", err, "t1")
+	assert.Equal(t, []int{0, 1}, indexes)
+
+	err = ValidateWithContext(ctx, map[string]string{"abc": "x", "y": "abc"}, EachWithKeyWithContext(func(ctx context.Context, key, value interface{}) []Rule {
+		if key == ctx.Value(contains) {
+			return []Rule{Required}
+		}
+		return []Rule{In(ctx.Value(contains))}
+	}))
+	assert.NoError(t, err)
+
+	err = ValidateWithContext(ctx, map[string]string{"y": "x"}, EachWithKeyWithContext(func(ctx context.Context, key, value interface{}) []Rule {
+		return []Rule{In(ctx.Value(contains))}
+	}))
+	assertError(t, "y: must be a valid value.", err, "t2")
+}
@@ -0,0 +1,116 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+type (
+	// Emptier is the interface that types may implement to define when their values are empty.
+	// It is honored by IsEmpty and thus by all rules that check or skip empty values, such as Required,
+	// NilOrNotEmpty and Empty.
+	Emptier interface {
+		// IsEmpty reports whether the value is empty.
+		IsEmpty() bool
+	}
+
+	// zeroer is implemented by types that report their zero values, such as time.Time.
+	zeroer interface {
+		IsZero() bool
+	}
+
+	// EmptyFunc reports whether a value is empty.
+	EmptyFunc func(value interface{}) bool
+
+	// emptyMethod indicates how the emptiness of the values of a type is determined by their methods.
+	emptyMethod int
+)
+
+const (
+	noEmptyMethod emptyMethod = iota
+	emptierMethod
+	zeroerMethod
+	// the methods declared with a pointer receiver
+	ptrEmptierMethod
+	ptrZeroerMethod
+)
+
+var (
+	emptyFuncs   sync.Map // reflect.Type => EmptyFunc
+	emptyMethods sync.Map // reflect.Type => emptyMethod
+	// emptyFuncsUsed indicates whether any function has been registered, so that the lookups are skipped until then.
+	emptyFuncsUsed atomic.Bool
+
+	emptierType = reflect.TypeOf((*Emptier)(nil)).Elem()
+	zeroerType  = reflect.TypeOf((*zeroer)(nil)).Elem()
+)
+
+// RegisterEmptyFunc registers a function that determines whether the values of the same type as the given
+// value are empty. It takes precedence over the Emptier interface and the IsZero method of the type, and is
+// useful for types that cannot be changed, such as those of third-party packages. For example,
+//
+//	validation.RegisterEmptyFunc(uuid.UUID{}, func(value interface{}) bool {
+//	    return value.(uuid.UUID) == uuid.Nil
+//	})
+//
+// Passing a nil function removes the registration.
+func RegisterEmptyFunc(value interface{}, f EmptyFunc) {
+	t := reflect.TypeOf(value)
+	if f == nil {
+		emptyFuncs.Delete(t)
+		return
+	}
+	emptyFuncs.Store(t, f)
+	emptyFuncsUsed.Store(true)
+}
+
+// isEmptyByType checks if a value, given also as its reflect.Value, is empty using the function registered for its type or its
+// IsEmpty or IsZero method. The second return value indicates whether any of these is found.
+func isEmptyByType(value interface{}, v reflect.Value) (bool, bool) {
+	t := v.Type()
+	if emptyFuncsUsed.Load() {
+		if f, ok := emptyFuncs.Load(t); ok {
+			return f.(EmptyFunc)(value), true
+		}
+	}
+	if isPredeclared(value) {
+		// predeclared types have no methods
+		return false, false
+	}
+	switch getEmptyMethod(t) {
+	case emptierMethod:
+		return v.Interface().(Emptier).IsEmpty(), true
+	case zeroerMethod:
+		return v.Interface().(zeroer).IsZero(), true
+	case ptrEmptierMethod:
+		return addressOf(v).Interface().(Emptier).IsEmpty(), true
+	case ptrZeroerMethod:
+		return addressOf(v).Interface().(zeroer).IsZero(), true
+	}
+	return false, false
+}
+
+// getEmptyMethod returns how the emptiness of the values of a type is determined by their methods.
+func getEmptyMethod(t reflect.Type) emptyMethod {
+	if m, ok := emptyMethods.Load(t); ok {
+		return m.(emptyMethod)
+	}
+	m := noEmptyMethod
+	switch pt := reflect.PtrTo(t); {
+	case t.Implements(emptierType):
+		m = emptierMethod
+	case t.Kind() != reflect.Ptr && pt.Implements(emptierType):
+		m = ptrEmptierMethod
+	case t.Implements(zeroerType):
+		m = zeroerMethod
+	case t.Kind() != reflect.Ptr && pt.Implements(zeroerType):
+		m = ptrZeroerMethod
+	}
+	emptyMethods.Store(t, m)
+	return m
+}
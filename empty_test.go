@@ -0,0 +1,105 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	// emptyMoney implements Emptier with a value receiver.
+	emptyMoney struct {
+		Amount   int64
+		Currency string
+	}
+
+	// zeroID has an IsZero method with a pointer receiver.
+	zeroID [4]byte
+
+	// emptyList implements Emptier with a pointer receiver.
+	emptyList struct {
+		items []string
+	}
+
+	// registeredID has an emptiness function registered.
+	registeredID [2]byte
+)
+
+func (m emptyMoney) IsEmpty() bool {
+	return m.Amount == 0
+}
+
+func (id *zeroID) IsZero() bool {
+	return *id == zeroID{}
+}
+
+func (l *emptyList) IsEmpty() bool {
+	return len(l.items) == 0
+}
+
+func TestIsEmpty_Custom(t *testing.T) {
+	RegisterEmptyFunc(registeredID{}, func(value interface{}) bool {
+		return value.(registeredID) == registeredID{}
+	})
+	defer RegisterEmptyFunc(registeredID{}, nil)
+
+	var nilMoney *emptyMoney
+	loc := time.FixedZone("test", 3600)
+
+	tests := []struct {
+		tag   string
+		value interface{}
+		empty bool
+	}{
+		{"t1", emptyMoney{}, true},
+		{"t2", emptyMoney{Currency: "USD"}, true},
+		{"t3", emptyMoney{Amount: 1}, false},
+		{"t4", &emptyMoney{Currency: "USD"}, true},
+		{"t5", nilMoney, true},
+		{"t6", zeroID{}, true},
+		{"t7", zeroID{1}, false},
+		{"t8", emptyList{}, true},
+		{"t9", emptyList{items: []string{"a"}}, false},
+		{"t10", registeredID{}, true},
+		{"t11", registeredID{1}, false},
+		{"t12", time.Time{}.In(loc), true},
+		{"t13", time.Now(), false},
+		// arrays of other types keep the length semantics
+		{"t14", [2]byte{}, false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.empty, IsEmpty(test.value), test.tag)
+	}
+
+	// the registration takes precedence over the methods
+	RegisterEmptyFunc(emptyMoney{}, func(value interface{}) bool {
+		return value.(emptyMoney).Currency == ""
+	})
+	assert.False(t, IsEmpty(emptyMoney{Currency: "USD"}))
+	RegisterEmptyFunc(emptyMoney{}, nil)
+	assert.True(t, IsEmpty(emptyMoney{Currency: "USD"}))
+}
+
+func TestCustomEmptiness_Rules(t *testing.T) {
+	empty := emptyMoney{Currency: "USD"}
+	full := emptyMoney{Amount: 100, Currency: "USD"}
+
+	assert.Equal(t, ErrRequired, Required.Validate(empty))
+	assert.Nil(t, Required.Validate(full))
+	assert.Equal(t, ErrNilOrNotEmpty, NilOrNotEmpty.Validate(&empty))
+	assert.Nil(t, NilOrNotEmpty.Validate((*emptyMoney)(nil)))
+	assert.Nil(t, Empty.Validate(empty))
+	assert.Equal(t, ErrEmpty, Empty.Validate(full))
+	assert.Equal(t, ErrNil, Nil.Validate(empty))
+	assert.Nil(t, Nil.Validate((*emptyMoney)(nil)))
+
+	// rules skipping empty values
+	assert.Nil(t, In(full).Validate(empty))
+	assert.Nil(t, Validate(zeroID{}, Required.When(false), In(zeroID{1})))
+	assert.Equal(t, ErrRequired, Validate(zeroID{}, Required))
+}
@@ -1,8 +1,15 @@
 module github.com/go-ozzo/ozzo-validation/v4
 
-go 1.13
+go 1.22
 
 require (
 	github.com/asaskevich/govalidator v0.0.0-20210307081110-f21760c49a8d
 	github.com/stretchr/testify v1.4.0
+	golang.org/x/text v0.22.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
 )
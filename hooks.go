@@ -0,0 +1,115 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"reflect"
+)
+
+type (
+	// BeforeValidator is the interface implemented by the types that need to prepare themselves
+	// before being validated, such as by normalizing their fields. Declare the method with a pointer
+	// receiver to modify the value.
+	BeforeValidator interface {
+		// BeforeValidate is called before the value is validated. If it returns an error,
+		// the value is not validated and the error is returned.
+		BeforeValidate(ctx context.Context) error
+	}
+
+	// AfterValidator is the interface implemented by the types that need to run additional checks
+	// after being validated, such as checks involving multiple fields.
+	AfterValidator interface {
+		// AfterValidate is called after the value is validated, with the validation errors keyed by field names.
+		// errs is never nil, and the method may add errors to it. If the method returns Errors, they are merged
+		// into errs. If it returns any other error, the error is returned instead of errs.
+		AfterValidate(ctx context.Context, errs Errors) error
+	}
+)
+
+// callWithHooks calls f, which validates a value, between the BeforeValidate and AfterValidate hooks implemented
+// by the first of the given values implementing them. The given values should be the value and its address.
+// f is given the context carrying the validation state, which is nil if the validation is not context-aware.
+// The hooks are given the context of the caller, or context.Background() if the validation is not context-aware.
+//
+// AfterValidate is not called if f returns an error other than Errors. The type being validated is recorded
+// in the validation state, so that ValidateStructWithContext called by f for the same value does not call
+// the hooks again.
+func callWithHooks(ctx context.Context, st validationState, vs []reflect.Value, f func(ctx context.Context) error) error {
+	var (
+		before BeforeValidator
+		after  AfterValidator
+		typ    reflect.Type
+	)
+	for _, v := range vs {
+		if !v.IsValid() || !v.CanInterface() {
+			continue
+		}
+		if typ == nil {
+			typ = indirectType(v.Type())
+		}
+		if b, ok := v.Interface().(BeforeValidator); ok && before == nil {
+			before = b
+		}
+		if a, ok := v.Interface().(AfterValidator); ok && after == nil {
+			after = a
+		}
+	}
+	if before == nil && after == nil {
+		return f(withState(ctx, st))
+	}
+
+	st.hooked = typ
+	sctx := withState(ctx, st)
+	return runHooks(userContext(ctx), before, after, func() error { return f(sctx) })
+}
+
+// runHooks calls f between the given hooks, either of which may be nil.
+func runHooks(ctx context.Context, before BeforeValidator, after AfterValidator, f func() error) error {
+	if before != nil {
+		if err := before.BeforeValidate(ctx); err != nil {
+			return err
+		}
+	}
+	err := f()
+	if after == nil {
+		return err
+	}
+
+	errs, ok := err.(Errors)
+	if err != nil && !ok {
+		return err
+	}
+	if errs == nil {
+		errs = Errors{}
+	}
+	if err := after.AfterValidate(ctx, errs); err != nil {
+		es, ok := err.(Errors)
+		if !ok {
+			return err
+		}
+		mergeErrors(errs, es)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// structHooks returns the hooks implemented by the given struct pointer, unless they are already being called
+// around the validation method that validates the struct at the level of the given state. If the validation is
+// not context-aware, the hooks of a Validatable struct are not returned either, as they are called around its
+// Validate() method by Validate(), which cannot pass the state to the method.
+func structHooks(ctx context.Context, st validationState, structPtr reflect.Value) (BeforeValidator, AfterValidator) {
+	if st.hooked == structPtr.Type().Elem() {
+		return nil, nil
+	}
+	if _, ok := structPtr.Interface().(Validatable); ok && ctx == nil {
+		return nil, nil
+	}
+	before, _ := structPtr.Interface().(BeforeValidator)
+	after, _ := structPtr.Interface().(AfterValidator)
+	return before, after
+}
@@ -0,0 +1,147 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hookedRange struct {
+	Name   string
+	Min    int
+	Max    int
+	before int
+	after  int
+	failed bool
+	ctx    context.Context
+}
+
+func (r *hookedRange) BeforeValidate(ctx context.Context) error {
+	r.before++
+	r.ctx = ctx
+	r.Name = strings.TrimSpace(r.Name)
+	if r.failed {
+		return errors.New("before failed")
+	}
+	return nil
+}
+
+func (r *hookedRange) AfterValidate(ctx context.Context, errs Errors) error {
+	r.after++
+	if _, ok := errs["Min"]; !ok && r.Min > r.Max {
+		errs["Max"] = errors.New("must not be less than min")
+	}
+	return nil
+}
+
+func (r hookedRange) Validate() error {
+	return ValidateStruct(&r,
+		Field(&r.Name, Required, Length(0, 3)),
+		Field(&r.Min, Min(0)),
+	)
+}
+
+type hookedRangeCtx struct {
+	hookedRange
+}
+
+func (r *hookedRangeCtx) ValidateWithContext(ctx context.Context) error {
+	return ValidateStructWithContext(ctx, r,
+		Field(&r.Name, Required, Length(0, 3)),
+		Field(&r.Min, Min(0)),
+	)
+}
+
+type hookedStruct struct {
+	Name  string
+	after int
+	ctx   context.Context
+}
+
+func (s *hookedStruct) AfterValidate(ctx context.Context, errs Errors) error {
+	s.after++
+	s.ctx = ctx
+	if s.Name == "x" {
+		return Errors{"Name": errors.New("must not be x")}
+	}
+	if s.Name == "y" {
+		return NewInternalError(errors.New("after failed"))
+	}
+	return nil
+}
+
+func TestHooks_Validate(t *testing.T) {
+	r := &hookedRange{Name: " abc ", Min: 5, Max: 1}
+	err := Validate(r)
+	assertError(t, "Max: must not be less than min.", err, "t1")
+	assert.Equal(t, "abc", r.Name, "BeforeValidate normalizes the value")
+	assert.Equal(t, 1, r.before)
+	assert.Equal(t, 1, r.after)
+
+	r = &hookedRange{Name: "abc", Min: -1, Max: -2}
+	err = ValidateWithContext(context.Background(), r)
+	assertError(t, "Min: must be no less than 0.", err, "t2")
+	assert.Equal(t, 1, r.after)
+
+	r = &hookedRange{Name: "abc", Max: 1}
+	assert.NoError(t, Validate(r), "t3")
+
+	r = &hookedRange{Name: "abc", failed: true}
+	assertError(t, "before failed", Validate(r), "t4")
+	assert.Equal(t, 0, r.after, "AfterValidate is not called if BeforeValidate fails")
+
+	// hooks declared with a pointer receiver are called for struct fields
+	s := struct{ R hookedRange }{hookedRange{Name: " abcd "}}
+	err = ValidateStruct(&s, Field(&s.R))
+	assertError(t, "R: (Name: the length must be no more than 3.).", err, "t5")
+	assert.Equal(t, "abcd", s.R.Name)
+	assert.Equal(t, 1, s.R.after)
+
+	// hooks are called for elements
+	rs := []hookedRange{{Name: "a", Min: 2, Max: 1}, {Name: "b"}}
+	err = Validate(rs)
+	assertError(t, "0: (Max: must not be less than min.).", err, "t6")
+	assert.Equal(t, 1, rs[1].before)
+}
+
+func TestHooks_ValidateStructWithContext(t *testing.T) {
+	// the hooks are called once when ValidateStructWithContext is called by the validation method
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+	r := &hookedRangeCtx{hookedRange{Name: " a ", Min: 2, Max: 1}}
+	err := ValidateWithContext(ctx, r)
+	assertError(t, "Max: must not be less than min.", err, "t1")
+	assert.Equal(t, "a", r.Name)
+	assert.Equal(t, 1, r.before)
+	assert.Equal(t, 1, r.after)
+	assert.True(t, r.ctx == ctx, "the hooks are given the context of the caller")
+
+	r = &hookedRangeCtx{hookedRange{Name: "a", Min: 2, Max: 1}}
+	err = r.ValidateWithContext(context.Background())
+	assertError(t, "Max: must not be less than min.", err, "t2")
+	assert.Equal(t, 1, r.before)
+	assert.Equal(t, 1, r.after)
+
+	// a struct that is not Validatable
+	s := &hookedStruct{Name: "x"}
+	err = ValidateStructWithContext(ctx, s, Field(&s.Name, Length(2, 3)))
+	assertError(t, "Name: the length must be between 2 and 3.", err, "t3")
+	assert.Equal(t, 1, s.after)
+	assert.True(t, s.ctx == ctx, "the hooks are given the context of the caller")
+
+	s = &hookedStruct{Name: "y"}
+	err = ValidateStructWithContext(context.Background(), s)
+	if assert.Error(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+	}
+
+	// ValidateStruct calls the hooks with context.Background()
+	s = &hookedStruct{Name: "x"}
+	assertError(t, "Name: must not be x.", ValidateStruct(s), "t4")
+	assert.Equal(t, 1, s.after)
+	assert.True(t, s.ctx == context.Background())
+}
@@ -5,6 +5,7 @@
 package validation
 
 import (
+	"context"
 	"reflect"
 )
 
@@ -14,6 +15,7 @@ var ErrInInvalid = NewError("validation_in_invalid", "must be a valid value")
 // In returns a validation rule that checks if a value can be found in the given list of values.
 // reflect.DeepEqual() will be used to determine if two values are equal.
 // For more details please refer to https://golang.org/pkg/reflect/#DeepEqual
+// Call Coerce to compare numbers by value regardless of their types.
 // An empty value is considered valid. Use the Required rule to make sure a value is not empty.
 func In(values ...interface{}) InRule {
 	return InRule{
@@ -26,6 +28,7 @@ func In(values ...interface{}) InRule {
 type InRule struct {
 	elements []interface{}
 	err      Error
+	coerce   bool
 }
 
 // Validate checks if the given value is valid or not.
@@ -36,7 +39,7 @@ func (r InRule) Validate(value interface{}) error {
 	}
 
 	for _, e := range r.elements {
-		if reflect.DeepEqual(e, value) {
+		if reflect.DeepEqual(e, value) || r.coerce && numberEqual(e, value) {
 			return nil
 		}
 	}
@@ -44,6 +47,24 @@ func (r InRule) Validate(value interface{}) error {
 	return r.err
 }
 
+// ValidateWithContext checks if the given value is valid or not.
+// Numeric coercion is enabled if the context is created by WithCoercion.
+func (r InRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	if coercionEnabled(ctx) {
+		r.coerce = true
+	}
+	return r.Validate(value)
+}
+
+// Coerce enables numeric coercion. An element that is of an int, uint or float type, a json.Number or a string
+// in decimal notation then also matches a value of any of these types that is equal to it by value.
+// For example, In(1, 2, 3).Coerce() accepts 2, float64(2), json.Number("2") and "2.0", and
+// In(json.Number("2")).Coerce() accepts 2.
+func (r InRule) Coerce() InRule {
+	r.coerce = true
+	return r
+}
+
 // Error sets the error message for the rule.
 func (r InRule) Error(message string) InRule {
 	r.err = r.err.SetMessage(message)
@@ -5,6 +5,8 @@
 package validation
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -55,3 +57,33 @@ func TestInRule_ErrorObject(t *testing.T) {
 	assert.Equal(t, err.Code(), r.err.Code())
 	assert.Equal(t, err.Message(), r.err.Message())
 }
+
+func TestInRule_Coerce(t *testing.T) {
+	tests := []struct {
+		tag    string
+		values []interface{}
+		value  interface{}
+		err    string
+	}{
+		{"t1", []interface{}{1, 2}, float64(2), ""},
+		{"t2", []interface{}{1, 2}, json.Number("2"), ""},
+		{"t3", []interface{}{1, 2}, "2.0", ""},
+		{"t4", []interface{}{1, 2}, "3", "must be a valid value"},
+		{"t5", []interface{}{0.1}, "0.1", ""},
+		{"t6", []interface{}{"1", "2"}, 2, ""},
+		{"t7", []interface{}{"a", 2}, "a", ""},
+		{"t8", []interface{}{1, 2}, "x", "must be a valid value"},
+		{"t9", []interface{}{json.Number("2")}, 2, ""},
+		{"t10", []interface{}{json.Number("2")}, "2.0", ""},
+		{"t11", []interface{}{"x", json.Number("2")}, 3, "must be a valid value"},
+	}
+
+	for _, test := range tests {
+		err := In(test.values...).Coerce().Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	assert.NotNil(t, In(1, 2).ValidateWithContext(context.Background(), float64(2)))
+	assert.Nil(t, In(1, 2).ValidateWithContext(WithCoercion(context.Background()), float64(2)))
+	assert.Nil(t, Validate(2, In(json.Number("2")).Coerce()))
+}
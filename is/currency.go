@@ -0,0 +1,57 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package is
+
+import (
+	"github.com/asaskevich/govalidator"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// ErrCurrencyUnknown is the error that returns when an amount is checked against a currency without known minor units.
+var ErrCurrencyUnknown = validation.NewError("validation_is_currency_unknown", "cannot be checked against currency {{.code}}")
+
+// currencyMinorUnits lists the ISO 4217 currencies whose minor units differ from 2.
+// A negative value means the currency has no minor units, such as precious metals and testing codes.
+var currencyMinorUnits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+	"XAG": -1, "XAU": -1, "XBA": -1, "XBB": -1, "XBC": -1, "XBD": -1, "XDR": -1,
+	"XPD": -1, "XPT": -1, "XSU": -1, "XTS": -1, "XUA": -1, "XXX": -1,
+}
+
+// CurrencyMinorUnits returns the number of decimal places used by an ISO 4217 currency, such as 2 for USD,
+// 0 for JPY and 3 for KWD. The boolean result is false if the code is not a valid currency code accepted by
+// CurrencyCode or the currency has no minor units.
+func CurrencyMinorUnits(code string) (int, bool) {
+	if !govalidator.IsISO4217(code) {
+		return 0, false
+	}
+	units, ok := currencyMinorUnits[code]
+	if !ok {
+		return 2, true
+	}
+	return units, units >= 0
+}
+
+// CurrencyPrecision returns a validation rule that checks if an amount in the given ISO 4217 currency
+// fits the given number of total digits, with the scale being the minor units of the currency.
+// For example, CurrencyPrecision("USD", 12) is equivalent to validation.Precision(12, 2),
+// while CurrencyPrecision("JPY", 12) is equivalent to validation.Precision(12, 0).
+// If the currency minor units are unknown, the rule returns ErrCurrencyUnknown for any non-empty amount.
+// Use CurrencyCode to validate the currency code itself.
+func CurrencyPrecision(code string, totalDigits int) validation.Rule {
+	scale, ok := CurrencyMinorUnits(code)
+	if !ok {
+		return validation.By(func(value interface{}) error {
+			if value, isNil := validation.Indirect(value); isNil || validation.IsEmpty(value) {
+				return nil
+			}
+			return ErrCurrencyUnknown.SetParams(map[string]interface{}{"code": code})
+		})
+	}
+	return validation.Precision(totalDigits, scale)
+}
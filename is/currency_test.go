@@ -0,0 +1,62 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package is
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrencyMinorUnits(t *testing.T) {
+	tests := []struct {
+		code  string
+		units int
+		ok    bool
+	}{
+		{"USD", 2, true},
+		{"EUR", 2, true},
+		{"JPY", 0, true},
+		{"KWD", 3, true},
+		{"CLF", 4, true},
+		{"XAU", 0, false},
+		{"USS", 0, false},
+		{"", 0, false},
+	}
+	for _, test := range tests {
+		units, ok := CurrencyMinorUnits(test.code)
+		assert.Equal(t, test.ok, ok, test.code)
+		if test.ok {
+			assert.Equal(t, test.units, units, test.code)
+		}
+	}
+}
+
+func TestCurrencyPrecision(t *testing.T) {
+	tests := []struct {
+		code  string
+		value interface{}
+		err   string
+	}{
+		{"USD", "12.34", ""},
+		{"USD", "12.345", "must have no more than 2 decimal places"},
+		{"JPY", 1200, ""},
+		{"JPY", "12.5", "must have no more than 0 decimal places"},
+		{"KWD", 1.234, ""},
+		{"KWD", "123456789.1", "must have no more than 8 digits before the decimal point"},
+		{"XAU", "1.5", "cannot be checked against currency XAU"},
+		{"USS", "1.5", "cannot be checked against currency USS"},
+		{"USS", "", ""},
+		{"USS", nil, ""},
+	}
+	for _, test := range tests {
+		err := CurrencyPrecision(test.code, 11).Validate(test.value)
+		if test.err == "" {
+			assert.Nil(t, err, test.code)
+		} else if assert.NotNil(t, err, test.code) {
+			assert.Equal(t, test.err, err.Error(), test.code)
+		}
+	}
+}
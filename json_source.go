@@ -0,0 +1,310 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	// SourceError is a validation error located in the JSON document the validated value is decoded from.
+	SourceError struct {
+		// Path is the dot-separated path of the error, such as "items.1023.price".
+		Path string
+		// Offset is the byte offset of the value the error is located at.
+		Offset int
+		// Line is the one-based line number of the value.
+		Line int
+		// Column is the one-based column of the value, counted in characters.
+		Column int
+		// Err is the validation error.
+		Err error
+	}
+
+	// SourceErrors represents a list of validation errors located in a JSON document.
+	SourceErrors []SourceError
+
+	// jsonLocator scans a JSON document for the offsets of the values and the object keys at the given paths.
+	// The paths are keyed by pathKey(), so that keys containing dots do not collide with nested keys.
+	jsonLocator struct {
+		data   []byte
+		pos    int
+		want   map[string]bool
+		values map[string]int
+		keys   map[string]int
+	}
+
+	// pathError is a validation error with the segments of its path.
+	pathError struct {
+		path []string
+		err  error
+	}
+)
+
+// LocateJSONErrors locates the validation errors of a value in the JSON document the value is decoded from.
+// The error paths, which are formed by the keys of Errors such as "items.1023.price", are mapped to the positions
+// of the corresponding values in the document by scanning its tokens. For example,
+//
+//	var order Order
+//	if err := json.Unmarshal(data, &order); err != nil {
+//	    return err
+//	}
+//	if err := order.Validate(); err != nil {
+//	    located, _ := validation.LocateJSONErrors(data, err)
+//	    fmt.Println(located)
+//	    // items.1023.price (line 4198, column 16): must be no less than 0
+//	}
+//
+// The keys of Errors must be the JSON keys, which is the case for struct fields validated with the default
+// ErrorTag. An error reported under StructErrorKey is located at the struct, and an error of a map key reported
+// by EachKey or EachEntry is located at the key. An error whose value is missing in the document, such as a
+// missing required key, is located at the nearest enclosing value. The errors are sorted by their positions.
+//
+// If err is not Errors, it is located at the whole document. If err is nil, nil is returned. An internal error,
+// or a document that is not valid JSON, is returned as the error.
+func LocateJSONErrors(data []byte, err error) (SourceErrors, error) {
+	if err == nil {
+		return nil, nil
+	}
+	if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+		return nil, err
+	}
+	var raw json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	var errs []pathError
+	if es, ok := err.(Errors); ok {
+		errs = flattenErrors(es, nil, nil)
+	} else {
+		errs = []pathError{{err: err}}
+	}
+
+	l := &jsonLocator{data: data, want: map[string]bool{"": true}, values: map[string]int{}, keys: map[string]int{}}
+	for _, pe := range errs {
+		for i := 1; i <= len(pe.path); i++ {
+			l.want[pathKey(pe.path[:i])] = true
+		}
+	}
+	l.value("", true)
+
+	lines := lineStarts(data)
+	located := make(SourceErrors, len(errs))
+	for i, pe := range errs {
+		path := strings.Join(pe.path, ".")
+		offset, found := 0, false
+		if _, ok := pe.err.(KeyError); ok {
+			offset, found = l.keys[pathKey(pe.path)]
+		}
+		for n := len(pe.path); !found && n >= 0; n-- {
+			offset, found = l.values[pathKey(pe.path[:n])]
+		}
+		line := sort.Search(len(lines), func(i int) bool { return lines[i] > offset })
+		located[i] = SourceError{
+			Path:   path,
+			Offset: offset,
+			Line:   line,
+			Column: utf8.RuneCount(data[lines[line-1]:offset]) + 1,
+			Err:    pe.err,
+		}
+	}
+	sort.SliceStable(located, func(i, j int) bool {
+		if located[i].Offset != located[j].Offset {
+			return located[i].Offset < located[j].Offset
+		}
+		return located[i].Path < located[j].Path
+	})
+	return located, nil
+}
+
+// flattenErrors returns the errors nested in Errors with their paths, sorted by their paths.
+// The errors reported under StructErrorKey have the path of the enclosing Errors.
+func flattenErrors(es Errors, path []string, result []pathError) []pathError {
+	keys := make([]string, 0, len(es))
+	for key := range es {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		err := es[key]
+		if err == nil {
+			continue
+		}
+		p := path
+		if key != StructErrorKey {
+			p = append(path[:len(path):len(path)], key)
+		}
+		if nested, ok := err.(Errors); ok {
+			result = flattenErrors(nested, p, result)
+		} else {
+			result = append(result, pathError{path: p, err: err})
+		}
+	}
+	return result
+}
+
+// lineStarts returns the offsets of the beginnings of the lines in the given data.
+func lineStarts(data []byte) []int {
+	starts := []int{0}
+	for i, b := range data {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// value scans a value at the given path. If track is true, the offsets of the values and the keys inside
+// the value whose paths are wanted are recorded. The document is known to be valid JSON.
+func (l *jsonLocator) value(path string, track bool) {
+	l.skipSpace()
+	if track {
+		l.values[path] = l.pos
+	}
+	switch l.data[l.pos] {
+	case '{':
+		l.pos++
+		for {
+			if l.skipSpace(); l.data[l.pos] == '}' {
+				break
+			}
+			keyPos := l.pos
+			key := l.str()
+			l.skipSpace()
+			l.pos++ // the colon
+			p, t := "", false
+			if track {
+				p = joinPath(path, key)
+				if t = l.want[p]; t {
+					l.keys[p] = keyPos
+				}
+			}
+			l.value(p, t)
+			if l.skipSpace(); l.data[l.pos] == ',' {
+				l.pos++
+			}
+		}
+		l.pos++
+	case '[':
+		l.pos++
+		for i := 0; ; i++ {
+			if l.skipSpace(); l.data[l.pos] == ']' {
+				break
+			}
+			p, t := "", false
+			if track {
+				p = joinPath(path, strconv.Itoa(i))
+				t = l.want[p]
+			}
+			l.value(p, t)
+			if l.skipSpace(); l.data[l.pos] == ',' {
+				l.pos++
+			}
+		}
+		l.pos++
+	case '"':
+		l.str()
+	default:
+		// a number, true, false or null
+		for l.pos < len(l.data) && strings.IndexByte(",]} \t\r\n", l.data[l.pos]) < 0 {
+			l.pos++
+		}
+	}
+}
+
+// pathSegmentEscaper escapes the dots and the backslashes in the segments of a path key.
+var pathSegmentEscaper = strings.NewReplacer(`\`, `\\`, ".", `\.`)
+
+// pathKey returns the key of a path given as segments. Each segment is preceded by a dot and has its dots
+// and backslashes escaped, so that different paths, such as ["a.b"] and ["a", "b"], have different keys.
+// The key of the document itself is "".
+func pathKey(segments []string) string {
+	key := ""
+	for _, s := range segments {
+		key = joinPath(key, s)
+	}
+	return key
+}
+
+// joinPath appends a segment to a path key. See pathKey().
+func joinPath(path, key string) string {
+	return path + "." + pathSegmentEscaper.Replace(key)
+}
+
+// str scans a string and returns its value.
+func (l *jsonLocator) str() string {
+	start := l.pos
+	escaped := false
+	for l.pos++; l.data[l.pos] != '"'; l.pos++ {
+		if l.data[l.pos] == '\\' {
+			escaped = true
+			l.pos++
+		}
+	}
+	l.pos++
+	if !escaped {
+		return string(l.data[start+1 : l.pos-1])
+	}
+	var s string
+	_ = json.Unmarshal(l.data[start:l.pos], &s)
+	return s
+}
+
+// skipSpace skips white space.
+func (l *jsonLocator) skipSpace() {
+	for l.pos < len(l.data) {
+		switch l.data[l.pos] {
+		case ' ', '\t', '\r', '\n':
+			l.pos++
+		default:
+			return
+		}
+	}
+}
+
+// Error returns the error string of SourceError.
+func (e SourceError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("line %v, column %v: %v", e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("%v (line %v, column %v): %v", e.Path, e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the validation error.
+func (e SourceError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON converts the SourceError into a JSON object with the path, the position, the message
+// and the code of the error, which can be used in problem details responses.
+func (e SourceError) MarshalJSON() ([]byte, error) {
+	v := struct {
+		Path    string `json:"path"`
+		Line    int    `json:"line"`
+		Column  int    `json:"column"`
+		Offset  int    `json:"offset"`
+		Message string `json:"message"`
+		Code    string `json:"code,omitempty"`
+	}{Path: e.Path, Line: e.Line, Column: e.Column, Offset: e.Offset, Message: e.Err.Error()}
+	if ve, ok := e.Err.(Error); ok {
+		v.Code = ve.Code()
+	}
+	return json.Marshal(v)
+}
+
+// Error returns the error string of SourceErrors.
+func (es SourceErrors) Error() string {
+	s := make([]string, len(es))
+	for i, e := range es {
+		s[i] = e.Error()
+	}
+	return strings.Join(s, "\n")
+}
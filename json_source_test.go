@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocateJSONErrors(t *testing.T) {
+	data := []byte(`{
+  "name": "",
+  "items": [
+    {"sku": "a", "price": 1},
+    {"sku": "b", "price": -1, "tags": {"x y": "é"}}
+  ],
+  "meta": {"café": {"ok": true}}
+}`)
+
+	errs := Errors{
+		"name": ErrRequired,
+		"items": Errors{
+			"1": Errors{
+				"price": ErrMinGreaterEqualThanRequired.SetParams(map[string]interface{}{"threshold": 0}),
+				"tags":  Errors{"x y": KeyError{Err: errors.New("must not contain spaces")}},
+			},
+			StructErrorKey: errors.New("must have unique SKUs"),
+		},
+		"meta": Errors{
+			"café":  Errors{"ok": errors.New("must be false")},
+			"owner": ErrKeyMissing,
+		},
+	}
+	located, err := LocateJSONErrors(data, errs)
+	assert.NoError(t, err)
+	assert.Equal(t, `name (line 2, column 11): cannot be blank
+items (line 3, column 12): must have unique SKUs
+items.1.price (line 5, column 27): must be no less than 0
+items.1.tags.x y (line 5, column 40): invalid key: must not contain spaces
+meta.owner (line 7, column 11): required key is missing
+meta.café.ok (line 7, column 27): must be false`, located.Error())
+	if assert.Len(t, located, 6) {
+		assert.Equal(t, SourceError{Path: "name", Offset: 12, Line: 2, Column: 11, Err: ErrRequired}, located[0])
+		assert.Equal(t, "meta.owner", located[4].Path, "a missing value is located at the enclosing value")
+	}
+
+	b, err := json.Marshal(located[:1])
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"path":"name","line":2,"column":11,"offset":12,"message":"cannot be blank","code":"validation_required"}]`, string(b))
+
+	// an error that is not Errors is located at the document
+	located, err = LocateJSONErrors([]byte("\n  [1, 2]"), errors.New("must be sorted"))
+	assert.NoError(t, err)
+	assert.Equal(t, SourceErrors{{Offset: 3, Line: 2, Column: 3, Err: errors.New("must be sorted")}}, located)
+	assert.Equal(t, "line 2, column 3: must be sorted", located.Error())
+
+	located, err = LocateJSONErrors(data, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, located)
+
+	_, err = LocateJSONErrors([]byte(`{"a":`), errs)
+	assert.Error(t, err)
+
+	_, err = LocateJSONErrors(data, NewInternalError(errors.New("failed")))
+	assertError(t, "failed", err, "t1")
+
+	// keys containing dots do not collide with nested keys
+	data = []byte(`{"a.b": 1, "a": {"b": 2}, "c\\": 3, "c": {"\\": 4}, "": 5}`)
+	located, err = LocateJSONErrors(data, Errors{
+		"a.b": errors.New("e1"),
+		"a":   Errors{"b": errors.New("e2")},
+		"c\\": errors.New("e3"),
+		"c":   Errors{"\\": errors.New("e4")},
+		"":    errors.New("e5"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `a.b (line 1, column 9): e1
+a.b (line 1, column 23): e2
+c\ (line 1, column 34): e3
+c.\ (line 1, column 49): e4
+line 1, column 57: e5`, located.Error())
+}
+
+func TestLocateJSONErrors_Validate(t *testing.T) {
+	type item struct {
+		Price int `json:"price"`
+	}
+	type order struct {
+		Items []item `json:"items"`
+	}
+	data := []byte("{\"items\": [\n{\"price\": 1},\n{\"price\": -2}\n]}")
+	var o order
+	assert.NoError(t, json.Unmarshal(data, &o))
+	err := ValidateStruct(&o, Field(&o.Items, Each(By(func(value interface{}) error {
+		i := value.(item)
+		return ValidateStruct(&i, Field(&i.Price, Min(0)))
+	}))))
+	located, lerr := LocateJSONErrors(data, err)
+	assert.NoError(t, lerr)
+	assert.Equal(t, "items.1.price (line 3, column 11): must be no less than 0", located.Error())
+}
@@ -0,0 +1,126 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrNilLazyRule is the error that the function given to Lazy returns a nil rule.
+var ErrNilLazyRule = errors.New("the lazy rule function must not return nil")
+
+// ErrRuleNotRegistered is the error that a rule referenced by Ref is not registered.
+type ErrRuleNotRegistered string
+
+// Error returns the error string of ErrRuleNotRegistered.
+func (e ErrRuleNotRegistered) Error() string {
+	return fmt.Sprintf("rule %q is not registered", string(e))
+}
+
+var (
+	namedRulesMu sync.RWMutex
+	namedRules   = map[string]Rule{}
+)
+
+type (
+	lazyRule struct {
+		resolve func() (Rule, error)
+		mu      sync.Mutex
+		rule    atomic.Value // resolvedRule
+	}
+
+	resolvedRule struct {
+		rule Rule
+	}
+)
+
+// Lazy returns a validation rule that calls the given function to obtain the actual rule when the rule
+// is used for the first time. The resolved rule is then reused. Lazy makes it possible to declare rules
+// for recursive data, where a rule needs to reference itself.
+// For example,
+//
+//	var comment validation.Rule
+//	comment = validation.Map(
+//	    validation.Key("text", validation.Required),
+//	    validation.Key("replies", validation.Each(validation.Lazy(func() validation.Rule { return comment }))).Optional(),
+//	)
+//
+// An ErrNilLazyRule internal error is returned if the function returns nil. Recursion through lazy rules
+// is subject to the depth limit described in MaxDepth.
+func Lazy(f func() Rule) Rule {
+	return &lazyRule{resolve: func() (Rule, error) {
+		if rule := f(); rule != nil {
+			return rule, nil
+		}
+		return nil, ErrNilLazyRule
+	}}
+}
+
+// Ref returns a validation rule that refers to the rule registered under the given name via RegisterRule.
+// The reference is resolved when the rule is used for the first time, so a rule may refer to itself or to
+// rules registered later. An ErrRuleNotRegistered internal error is returned if no rule is registered
+// under the name when the rule is used.
+func Ref(name string) Rule {
+	return &lazyRule{resolve: func() (Rule, error) {
+		namedRulesMu.RLock()
+		defer namedRulesMu.RUnlock()
+		if rule, ok := namedRules[name]; ok && rule != nil {
+			return rule, nil
+		}
+		return nil, ErrRuleNotRegistered(name)
+	}}
+}
+
+// RegisterRule registers a rule under the given name so that it can be referenced by Ref.
+// Registering a rule under an existing name replaces the previous rule for references
+// that are not resolved yet.
+func RegisterRule(name string, rule Rule) {
+	namedRulesMu.Lock()
+	defer namedRulesMu.Unlock()
+	namedRules[name] = rule
+}
+
+// Validate checks if the given value is valid or not.
+func (r *lazyRule) Validate(value interface{}) error {
+	return r.ValidateWithContext(nil, value)
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+func (r *lazyRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	return r.validateWithState(ctx, stateOf(ctx), value)
+}
+
+func (r *lazyRule) validateWithState(ctx context.Context, st validationState, value interface{}) error {
+	rule, err := r.get()
+	if err != nil {
+		return NewInternalError(err)
+	}
+	if st, err = st.enter(); err != nil {
+		return err
+	}
+	return applyRule(ctx, st, rule, value)
+}
+
+// get returns the resolved rule, resolving it if needed.
+func (r *lazyRule) get() (Rule, error) {
+	if rr, ok := r.rule.Load().(resolvedRule); ok {
+		return rr.rule, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rr, ok := r.rule.Load().(resolvedRule); ok {
+		return rr.rule, nil
+	}
+	rule, err := r.resolve()
+	if err != nil {
+		return nil, err
+	}
+	r.rule.Store(resolvedRule{rule})
+	return rule, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazy(t *testing.T) {
+	calls := 0
+	var comment Rule
+	comment = Map(
+		Key("text", Required),
+		Key("replies", Each(Lazy(func() Rule {
+			calls++
+			return comment
+		}))).Optional(),
+	)
+
+	var doc map[string]interface{}
+	err := json.Unmarshal([]byte(`{"text": "a", "replies": [{"text": "b"}, {"text": "", "replies": [{"text": ""}]}]}`), &doc)
+	assert.Nil(t, err)
+
+	err = Validate(doc, comment)
+	assert.EqualError(t, err, "replies: (1: (replies: (0: (text: cannot be blank.).); text: cannot be blank.).).")
+	err = ValidateWithContext(context.Background(), doc, comment)
+	assert.EqualError(t, err, "replies: (1: (replies: (0: (text: cannot be blank.).); text: cannot be blank.).).")
+	assert.Equal(t, 1, calls)
+
+	// struct fields
+	s := struct {
+		Tree map[string]interface{}
+	}{Tree: doc}
+	err = ValidateStruct(&s, Field(&s.Tree, Lazy(func() Rule { return comment })))
+	assert.EqualError(t, err, "Tree: (replies: (1: (replies: (0: (text: cannot be blank.).); text: cannot be blank.).).).")
+
+	// a nil rule
+	err = Validate("abc", Lazy(func() Rule { return nil }))
+	assert.Equal(t, NewInternalError(ErrNilLazyRule), err)
+}
+
+func TestLazy_MaxDepth(t *testing.T) {
+	var node Rule
+	node = Map(Key("child", Lazy(func() Rule { return node })).Optional())
+
+	doc := map[string]interface{}{}
+	current := doc
+	for i := 0; i < 100; i++ {
+		child := map[string]interface{}{}
+		current["child"] = child
+		current = child
+	}
+	assert.Nil(t, Validate(doc, node))
+
+	err := ValidateWithContext(WithMaxDepth(context.Background(), 50), doc, node)
+	if assert.NotNil(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+		assert.True(t, strings.HasPrefix(err.Error(), "maximum validation depth of 50 exceeded at child.child."))
+	}
+
+	// a lazy rule referencing itself does not recurse forever
+	var self Rule
+	self = Lazy(func() Rule { return self })
+	err = Validate("abc", self)
+	if assert.NotNil(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+	}
+}
+
+func TestRef(t *testing.T) {
+	category := Map(
+		Key("name", Required),
+		Key("children", Each(Ref("test.category"))).Optional(),
+	)
+	doc := map[string]interface{}{
+		"name": "a",
+		"children": []interface{}{
+			map[string]interface{}{"name": ""},
+		},
+	}
+
+	err := Validate(doc, Ref("test.undefined"))
+	assert.Equal(t, NewInternalError(ErrRuleNotRegistered("test.undefined")), err)
+	assert.EqualError(t, err, `rule "test.undefined" is not registered`)
+
+	ref := Ref("test.category")
+	assert.NotNil(t, Validate(doc, ref))
+
+	RegisterRule("test.category", category)
+	err = Validate(doc, ref)
+	assert.EqualError(t, err, "children: (0: (name: cannot be blank.).).")
+}
+
+func TestLazy_Concurrent(t *testing.T) {
+	calls := 0
+	rule := Lazy(func() Rule {
+		calls++
+		return Required
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, ErrRequired, Validate("", rule))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, calls)
+}
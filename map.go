@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
 )
 
 var (
@@ -19,6 +21,9 @@ var (
 
 	// ErrKeyUnexpected is the error returned in case of an unexpected key.
 	ErrKeyUnexpected = NewError("validation_key_unexpected", "key not expected")
+
+	// ErrKeyNotMap is the error returned in case of a value on a key path that is not a map.
+	ErrKeyNotMap = NewError("validation_key_not_map", "must be a map")
 )
 
 type (
@@ -28,11 +33,14 @@ type (
 		allowExtraKeys bool
 	}
 
-	// KeyRules represents a rule set associated with a map key.
+	// KeyRules represents a rule set associated with a map key, or with the map keys matching a pattern.
 	KeyRules struct {
 		key      interface{}
 		optional bool
 		rules    []Rule
+		pattern  *regexp.Regexp
+		// path is the sequence of keys specified via KeyPath().
+		path []string
 	}
 )
 
@@ -69,7 +77,8 @@ func DynamicMap(keys ...*KeyRules) MapRule {
 	return MapRule{keys: keys, allowExtraKeys: true}
 }
 
-// AllowExtraKeys configures the rule to ignore extra keys.
+// AllowExtraKeys configures the rule to ignore extra keys, i.e., the keys that are neither specified via Key()
+// nor matched by PatternKey(). The rules of PatternKey() still apply to the matching keys.
 func (r MapRule) AllowExtraKeys() MapRule {
 	r.allowExtraKeys = true
 	return r
@@ -82,6 +91,10 @@ func (r MapRule) Validate(m interface{}) error {
 
 // ValidateWithContext checks if the given value is valid or not.
 func (r MapRule) ValidateWithContext(ctx context.Context, m interface{}) error {
+	return r.validateWithState(ctx, stateOf(ctx), m)
+}
+
+func (r MapRule) validateWithState(ctx context.Context, st validationState, m interface{}) error {
 	value := reflect.ValueOf(m)
 	if value.Kind() == reflect.Ptr {
 		value = value.Elem()
@@ -95,6 +108,16 @@ func (r MapRule) ValidateWithContext(ctx context.Context, m interface{}) error {
 		return nil
 	}
 
+	for _, kr := range r.keys {
+		if kr.path != nil {
+			// the element types of the nested maps are checked when the default values are stored
+			continue
+		}
+		if err := checkDefaults(kr.rules, value.Type().Elem()); err != nil {
+			return NewInternalError(err)
+		}
+	}
+
 	errs := Errors{}
 	kt := value.Type().Key()
 
@@ -107,21 +130,34 @@ func (r MapRule) ValidateWithContext(ctx context.Context, m interface{}) error {
 	}
 
 	for _, kr := range r.keys {
+		if kr.pattern != nil {
+			continue
+		}
+		if kr.path != nil {
+			if err := validateKeyPath(ctx, st, value, kr, errs); err != nil {
+				return err
+			}
+			if kv, ok := keyOnPath(kt, kr.path[0]); ok && !r.allowExtraKeys {
+				delete(extraKeys, kv.Interface())
+			}
+			continue
+		}
 		var err error
 		if kv := reflect.ValueOf(kr.key); !kt.AssignableTo(kv.Type()) {
 			err = ErrKeyWrongType
 		} else if vv := value.MapIndex(kv); !vv.IsValid() {
-			if !kr.optional {
+			if hasDefault(kr.rules) {
+				// validate the zero value so that the default value is stored into the map
+				err = validateEntry(ctx, st, value, kv, reflect.Zero(value.Type().Elem()), kr.rules)
+			} else if !kr.optional {
 				err = ErrKeyMissing
 			}
-		} else if ctx == nil {
-			err = Validate(vv.Interface(), kr.rules...)
 		} else {
-			err = ValidateWithContext(ctx, vv.Interface(), kr.rules...)
+			err = validateEntry(ctx, st, value, kv, vv, kr.rules)
 		}
 		if err != nil {
 			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
-				return err
+				return prependPath(err, getErrorKeyName(kr.key))
 			}
 			errs[getErrorKeyName(kr.key)] = err
 		}
@@ -130,6 +166,10 @@ func (r MapRule) ValidateWithContext(ctx context.Context, m interface{}) error {
 		}
 	}
 
+	if err := r.validatePatternKeys(ctx, st, value, errs, extraKeys); err != nil {
+		return err
+	}
+
 	if !r.allowExtraKeys {
 		for key := range extraKeys {
 			errs[getErrorKeyName(key)] = ErrKeyUnexpected
@@ -142,7 +182,189 @@ func (r MapRule) ValidateWithContext(ctx context.Context, m interface{}) error {
 	return nil
 }
 
+// validateKeyPath validates the map entry at the key path specified via KeyPath(). The validation error
+// is added to errs at the nested path. An internal error is returned if any.
+func validateKeyPath(ctx context.Context, st validationState, m reflect.Value, kr *KeyRules, errs Errors) error {
+	for i, name := range kr.path {
+		path := kr.path[:i+1]
+		kv, ok := keyOnPath(m.Type().Key(), name)
+		if !ok {
+			addPathError(errs, path, ErrKeyWrongType)
+			return nil
+		}
+		vv := m.MapIndex(kv)
+
+		if i < len(kr.path)-1 {
+			var next interface{}
+			isNil := true
+			if vv.IsValid() {
+				next, isNil = Indirect(vv.Interface())
+			}
+			if isNil {
+				if !kr.optional {
+					addPathError(errs, path, ErrKeyMissing)
+				}
+				return nil
+			}
+			if m = reflect.ValueOf(next); m.Kind() != reflect.Map {
+				addPathError(errs, path, ErrKeyNotMap)
+				return nil
+			}
+			continue
+		}
+
+		var err error
+		if vv.IsValid() {
+			err = validateEntry(ctx, st, m, kv, vv, kr.rules)
+		} else if hasDefault(kr.rules) {
+			// validate the zero value so that the default value is stored into the map
+			err = validateEntry(ctx, st, m, kv, reflect.Zero(m.Type().Elem()), kr.rules)
+		} else if !kr.optional {
+			err = ErrKeyMissing
+		}
+		if err != nil {
+			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+				return prependPath(err, strings.Join(path, "."))
+			}
+			addPathError(errs, path, err)
+		}
+	}
+	return nil
+}
+
+// keyOnPath returns the map key of the given key type for a key on a key path. The boolean result is false if
+// the key type is neither a string type nor an interface type that strings implement, such as interface{}
+// for the maps decoded from YAML.
+func keyOnPath(kt reflect.Type, name string) (reflect.Value, bool) {
+	kv := reflect.ValueOf(name)
+	switch {
+	case kt.Kind() == reflect.String:
+		return kv.Convert(kt), true
+	case kt.Kind() == reflect.Interface && kv.Type().Implements(kt):
+		return kv, true
+	}
+	return reflect.Value{}, false
+}
+
+// splitKeyPath splits a key path into keys at the dots that are not escaped with a backslash.
+// A backslash escapes the next character, so that `\.` and `\\` stand for a dot and a backslash in a key.
+func splitKeyPath(path string) []string {
+	var keys []string
+	var key strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case c == '\\' && i+1 < len(path):
+			i++
+			key.WriteByte(path[i])
+		case c == '.':
+			keys = append(keys, key.String())
+			key.Reset()
+		default:
+			key.WriteByte(c)
+		}
+	}
+	return append(keys, key.String())
+}
+
+// addPathError adds an error to errs at the given path of keys, nesting Errors for each key.
+// An existing error takes precedence over the new one, and existing Errors are merged with the new Errors.
+func addPathError(errs Errors, path []string, err error) {
+	for _, name := range path[:len(path)-1] {
+		existing, found := errs[name]
+		if !found {
+			existing = Errors{}
+			errs[name] = existing
+		}
+		es, ok := existing.(Errors)
+		if !ok {
+			return
+		}
+		errs = es
+	}
+	mergeErrors(errs, Errors{path[len(path)-1]: err})
+}
+
+// validatePatternKeys validates the map entries whose keys match the patterns specified via PatternKey().
+// The validation errors are added to errs unless errs already has an error for the key, and the matched keys
+// are removed from extraKeys. An internal error is returned if any.
+func (r MapRule) validatePatternKeys(ctx context.Context, st validationState, m reflect.Value, errs Errors, extraKeys map[interface{}]bool) error {
+	var patterns []*KeyRules
+	for _, kr := range r.keys {
+		if kr.pattern != nil {
+			patterns = append(patterns, kr)
+		}
+	}
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	for _, k := range m.MapKeys() {
+		ks, ok := stringKey(k)
+		if !ok {
+			continue
+		}
+		name := getErrorKeyName(k.Interface())
+		for _, kr := range patterns {
+			if !kr.pattern.MatchString(ks) {
+				continue
+			}
+			delete(extraKeys, k.Interface())
+			if _, found := errs[name]; found {
+				continue
+			}
+			err := validateEntry(ctx, st, m, k, m.MapIndex(k), kr.rules)
+			if err != nil {
+				if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+					return prependPath(err, name)
+				}
+				errs[name] = err
+			}
+		}
+	}
+	return nil
+}
+
+// stringKey returns the string value of a map key of a string type.
+func stringKey(k reflect.Value) (string, bool) {
+	if k.Kind() == reflect.Interface && !k.IsNil() {
+		k = k.Elem()
+	}
+	if k.Kind() != reflect.String {
+		return "", false
+	}
+	return k.String(), true
+}
+
+// withKey returns a copy of the rule that accepts the given key without validating it,
+// unless the key is already specified.
+func (r MapRule) withKey(key interface{}) MapRule {
+	for _, kr := range r.keys {
+		if kr.key == key {
+			return r
+		}
+	}
+	r.keys = append(r.keys[:len(r.keys):len(r.keys)], Key(key).Optional())
+	return r
+}
+
+// validateEntry validates a map entry with the given rules at the nesting level following the one of the given state. If the rules contain transformation rules,
+// the transformed value is stored back into the map.
+func validateEntry(ctx context.Context, st validationState, m, key, value reflect.Value, rules []Rule) error {
+	if !hasTransformer(rules) {
+		return validate(ctx, st, value.Interface(), reflect.Value{}, rules)
+	}
+	p := reflect.New(value.Type())
+	p.Elem().Set(value)
+	err := validate(ctx, st, value.Interface(), p, rules)
+	if ie, ok := err.(InternalError); !ok || ie.InternalError() == nil {
+		m.SetMapIndex(key, p.Elem())
+	}
+	return err
+}
+
 // Key specifies a map key and the corresponding validation rules.
+// Values transformed by transformation rules, such as Trim, are stored back into the map.
+// A missing key is set to the default value if the rules contain Default().
 func Key(key interface{}, rules ...Rule) *KeyRules {
 	return &KeyRules{
 		key:   key,
@@ -160,6 +382,47 @@ func OptionalKey(key interface{}, rules ...Rule) *KeyRules {
 	}
 }
 
+// KeyPath specifies a path of map keys separated by dots and the validation rules of the value at the path,
+// which is useful for validating nested documents decoded into maps, such as JSON and YAML. For example,
+//
+//	validation.Map(
+//	    validation.KeyPath("server.tls.cert", validation.Required),
+//	    validation.KeyPath("server.tls.key", validation.Required),
+//	)
+//
+// is similar to Map(Key("server", Map(Key("tls", Map(Key("cert", ...), Key("key", ...)))))), except that
+// the nested maps may have other keys, and a value on the path that is not a map is reported as ErrKeyNotMap
+// instead of an internal error. A key missing on the path is reported as ErrKeyMissing, unless the rule is
+// optional. A nil value on the path is considered missing. The errors are nested by the keys on the path,
+// e.g., "server: (tls: (cert: cannot be blank.).)." The maps on the path must have keys of a string type,
+// or of an interface type holding strings, such as the map[interface{}]interface{} values decoded from YAML.
+// A dot that is part of a key is escaped with a backslash, e.g., KeyPath(`annotations.example\.com/owner`).
+func KeyPath(path string, rules ...Rule) *KeyRules {
+	keys := splitKeyPath(path)
+	return &KeyRules{
+		key:   keys[0],
+		rules: rules,
+		path:  keys,
+	}
+}
+
+// PatternKey specifies the validation rules for the map keys that match the given regular expression,
+// like patternProperties in JSON Schema. The rules apply to every matching key of a string type,
+// including the keys specified via Key(). If a key matches multiple patterns, the rules of each pattern
+// are applied in the order they are specified, until an error is found. A key matching a pattern is not
+// considered an extra key. For example,
+//
+//	validation.Map(
+//	    validation.Key("name", validation.Required),
+//	    validation.PatternKey(regexp.MustCompile(`^x-`), validation.Length(1, 100)),
+//	)
+func PatternKey(pattern *regexp.Regexp, rules ...Rule) *KeyRules {
+	return &KeyRules{
+		pattern: pattern,
+		rules:   rules,
+	}
+}
+
 // Optional configures the rule to ignore the key if missing.
 func (r *KeyRules) Optional() *KeyRules {
 	r.optional = true
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"context"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -132,3 +133,110 @@ func TestMapWithContext(t *testing.T) {
 		assert.Equal(t, "Extra: key not expected; Value: the length must be between 5 and 10.", err.Error())
 	}
 }
+
+func TestMap_PatternKey(t *testing.T) {
+	ext := regexp.MustCompile(`^x-`)
+	m := map[string]interface{}{"name": "abc", "x-a": "1", "x-b": "", "extra": 1}
+	tests := []struct {
+		tag  string
+		rule MapRule
+		err  string
+	}{
+		{"t1", Map(Key("name", Required), PatternKey(ext, Required)), "extra: key not expected; x-b: cannot be blank."},
+		{"t2", Map(Key("name", Required), PatternKey(ext, Required)).AllowExtraKeys(), "x-b: cannot be blank."},
+		{"t3", Map(Key("name"), PatternKey(ext), Key("extra")), ""},
+		// the rules of a pattern apply to the explicit keys too
+		{"t4", Map(Key("name"), Key("extra"), PatternKey(regexp.MustCompile(`^n`), Length(5, 5))).AllowExtraKeys(), "name: the length must be exactly 5."},
+		// the error of an explicit key takes precedence
+		{"t5", DynamicMap(Key("name", Length(4, 4)), PatternKey(regexp.MustCompile(`^n`), Length(5, 5))), "name: the length must be exactly 4."},
+		// multiple patterns
+		{"t6", DynamicMap(PatternKey(ext, Required), PatternKey(regexp.MustCompile(`-a$`), Length(2, 2))), "x-a: the length must be exactly 2; x-b: cannot be blank."},
+	}
+	for _, test := range tests {
+		err := Validate(m, test.rule)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	// non-string keys do not match any pattern
+	err := Validate(map[int]string{1: "a"}, Map(PatternKey(regexp.MustCompile(`1`))))
+	assertError(t, "1: key not expected.", err, "t7")
+
+	// transformation rules store values back
+	m2 := map[string]string{"x-a": " a "}
+	assert.NoError(t, Validate(m2, Map(PatternKey(ext, Trim, Length(1, 1)))))
+	assert.Equal(t, "a", m2["x-a"])
+}
+
+func TestMap_KeyPath(t *testing.T) {
+	doc := map[string]interface{}{
+		"server": map[string]interface{}{
+			"port": 8080,
+			"tls": map[string]interface{}{
+				"cert": "cert.pem",
+				"key":  "",
+			},
+			"log": "stdout",
+		},
+		"name": "app",
+	}
+	tests := []struct {
+		tag  string
+		rule MapRule
+		err  string
+	}{
+		{"t1", Map(KeyPath("server.tls.cert", Required), KeyPath("server.port", Min(1)), Key("name")), ""},
+		{"t2", Map(KeyPath("server.tls.key", Required), KeyPath("server.port", Max(80)), Key("name")),
+			"server: (port: must be no greater than 80; tls: (key: cannot be blank.).)."},
+		{"t3", DynamicMap(KeyPath("server.tls.ca", Required)), "server: (tls: (ca: required key is missing.).)."},
+		{"t4", DynamicMap(KeyPath("server.tls.ca", Required).Optional()), ""},
+		{"t5", DynamicMap(KeyPath("server.log.level", Required)), "server: (log: must be a map.)."},
+		{"t6", DynamicMap(KeyPath("client.timeout", Required)), "client: required key is missing."},
+		{"t7", Map(KeyPath("server.tls.cert", Required)), "name: key not expected."},
+		// the errors of nested Map rules and key paths are merged
+		{"t8", DynamicMap(
+			Key("server", DynamicMap(Key("port", Max(80)))),
+			KeyPath("server.tls.key", Required),
+		), "server: (port: must be no greater than 80; tls: (key: cannot be blank.).)."},
+		// the error of a key takes precedence
+		{"t9", DynamicMap(
+			Key("server", Length(1, 2)),
+			KeyPath("server.tls.key", Required),
+		), "server: the length must be between 1 and 2."},
+	}
+	for _, test := range tests {
+		err := Validate(doc, test.rule)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	// nil values on the path are missing
+	err := Validate(map[string]interface{}{"a": nil}, Map(KeyPath("a.b", Required)))
+	assertError(t, "a: required key is missing.", err, "t10")
+
+	// keys of other types
+	err = Validate(map[string]map[int]string{"a": {1: "x"}}, Map(KeyPath("a.b", Required)))
+	assertError(t, "a: (b: key not the correct type.).", err, "t11")
+
+	// keys of interface types holding strings, as decoded from YAML
+	yamlDoc := map[interface{}]interface{}{
+		"server": map[interface{}]interface{}{"port": 0},
+		"name":   "app",
+	}
+	err = Validate(yamlDoc, Map(KeyPath("server.port", Required), KeyPath("name")))
+	assertError(t, "server: (port: cannot be blank.).", err, "t12")
+	err = Validate(map[error]interface{}{}, Map(KeyPath("a.b", Required)))
+	assertError(t, "a: key not the correct type.", err, "t13")
+
+	// escaped dots are part of the keys
+	labels := map[string]interface{}{
+		"labels": map[string]interface{}{"example.com/owner": "", `a\b`: "x"},
+	}
+	err = Validate(labels, Map(KeyPath(`labels.example\.com/owner`, Required), KeyPath(`labels.a\\b`, Required)))
+	assertError(t, "labels: (example.com/owner: cannot be blank.).", err, "t14")
+
+	// transformation rules and default values store values back into the nested maps
+	err = Validate(doc, DynamicMap(KeyPath("server.log", ToUpper), KeyPath("server.tls.version", Default("1.3"))))
+	assert.NoError(t, err)
+	server := doc["server"].(map[string]interface{})
+	assert.Equal(t, "STDOUT", server["log"])
+	assert.Equal(t, "1.3", server["tls"].(map[string]interface{})["version"])
+}
@@ -5,6 +5,7 @@
 package validation
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"time"
@@ -19,6 +20,10 @@ var (
 	ErrMinGreaterThanRequired = NewError("validation_min_greater_than_required", "must be greater than {{.threshold}}")
 	// ErrMaxLessThanRequired is the error that returns when a value is greater than or equal to a specified threshold.
 	ErrMaxLessThanRequired = NewError("validation_max_less_than_required", "must be less than {{.threshold}}")
+	// ErrBetweenRequired is the error that returns when a value is not within a specified range.
+	ErrBetweenRequired = NewError("validation_between_required", "must be between {{.min}} and {{.max}}")
+	// ErrBetweenExclusiveRequired is the error that returns when a value is not strictly within a specified range.
+	ErrBetweenExclusiveRequired = NewError("validation_between_exclusive_required", "must be greater than {{.min}} and less than {{.max}}")
 )
 
 // ThresholdRule is a validation rule that checks if a value satisfies the specified threshold requirement.
@@ -26,6 +31,13 @@ type ThresholdRule struct {
 	threshold interface{}
 	operator  int
 	err       Error
+	coerce    bool
+}
+
+// RangeRule is a validation rule that checks if a value is within the specified range.
+type RangeRule struct {
+	min, max ThresholdRule
+	err      Error
 }
 
 const (
@@ -37,8 +49,10 @@ const (
 
 // Min returns a validation rule that checks if a value is greater or equal than the specified value.
 // By calling Exclusive, the rule will check if the value is strictly greater than the specified value.
-// Note that the value being checked and the threshold value must be of the same type.
-// Only int, uint, float and time.Time types are supported.
+// Note that the value being checked and the threshold value must be of the same type,
+// unless numeric coercion is enabled by calling Coerce.
+// Supported types are int, uint, float, time.Time, *big.Int, *big.Rat, *big.Float and custom types implementing
+// Comparable. Values of math/big types and custom numeric types are compared by value with any number.
 // An empty value is considered valid. Please use the Required rule to make sure a value is not empty.
 func Min(min interface{}) ThresholdRule {
 	return ThresholdRule{
@@ -50,8 +64,9 @@ func Min(min interface{}) ThresholdRule {
 
 // Max returns a validation rule that checks if a value is less or equal than the specified value.
 // By calling Exclusive, the rule will check if the value is strictly less than the specified value.
-// Note that the value being checked and the threshold value must be of the same type.
-// Only int, uint, float and time.Time types are supported.
+// Note that the value being checked and the threshold value must be of the same type,
+// unless numeric coercion is enabled by calling Coerce.
+// Supported types are the same as for Min.
 // An empty value is considered valid. Please use the Required rule to make sure a value is not empty.
 func Max(max interface{}) ThresholdRule {
 	return ThresholdRule{
@@ -74,12 +89,29 @@ func (r ThresholdRule) Exclusive() ThresholdRule {
 	return r
 }
 
+// Coerce enables numeric coercion. The value being checked and a numeric threshold may then be of any
+// int, uint or float type, a json.Number or a string in decimal notation (such as "18", "-0.5" or "1e3"),
+// and they are compared by value. Values of integer types and strings are compared exactly, without overflow.
+// Floats are compared exactly by their shortest decimal representations, so that 0.1 equals "0.1" and
+// float64(9007199254740992) is less than int64(9007199254740993). A string that is not a number results
+// in ErrNotNumeric, and an empty string is considered valid.
+func (r ThresholdRule) Coerce() ThresholdRule {
+	r.coerce = true
+	return r
+}
+
 // Validate checks if the given value is valid or not.
 func (r ThresholdRule) Validate(value interface{}) error {
 	value, isNil := Indirect(value)
 	if isNil {
 		return nil
 	}
+	if r.coerce {
+		return r.validateNumber(value, true)
+	}
+	if isCustomNumber(value) {
+		return r.validateNumber(value, false)
+	}
 
 	rv := reflect.ValueOf(r.threshold)
 	switch rv.Kind() {
@@ -113,7 +145,7 @@ func (r ThresholdRule) Validate(value interface{}) error {
 	case reflect.Struct:
 		t, ok := r.threshold.(time.Time)
 		if !ok {
-			return fmt.Errorf("type not supported: %v", rv.Type())
+			return r.validateNumber(value, false)
 		}
 		v, ok := value.(time.Time)
 		if !ok {
@@ -123,6 +155,9 @@ func (r ThresholdRule) Validate(value interface{}) error {
 			return nil
 		}
 
+	case reflect.Ptr:
+		return r.validateNumber(value, false)
+
 	default:
 		return fmt.Errorf("type not supported: %v", rv.Type())
 	}
@@ -130,6 +165,44 @@ func (r ThresholdRule) Validate(value interface{}) error {
 	return r.err.SetParams(map[string]interface{}{"threshold": r.threshold})
 }
 
+// ValidateWithContext checks if the given value is valid or not.
+// Numeric coercion is enabled if the context is created by WithCoercion.
+func (r ThresholdRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	if coercionEnabled(ctx) {
+		r.coerce = true
+	}
+	return r.Validate(value)
+}
+
+// validateNumber checks a value against a threshold by comparing them by value.
+// If coerce is true, numeric strings are accepted too.
+func (r ThresholdRule) validateNumber(value interface{}, coerce bool) error {
+	if coerce {
+		if _, ok := r.threshold.(time.Time); ok {
+			r.coerce = false
+			return r.Validate(value)
+		}
+		if isEmptyString(value) {
+			return nil
+		}
+		if s, ok := value.(string); ok {
+			if _, ok := r.threshold.(time.Duration); ok {
+				if d, err := time.ParseDuration(s); err == nil {
+					value = d
+				}
+			}
+		}
+	}
+	c, err := compareValues(value, r.threshold, coerce)
+	if err != nil {
+		return err
+	}
+	if r.compareOrder(c) {
+		return nil
+	}
+	return r.err.SetParams(map[string]interface{}{"threshold": r.threshold})
+}
+
 // Error sets the error message for the rule.
 func (r ThresholdRule) Error(message string) ThresholdRule {
 	r.err = r.err.SetMessage(message)
@@ -142,6 +215,69 @@ func (r ThresholdRule) ErrorObject(err Error) ThresholdRule {
 	return r
 }
 
+// Between returns a validation rule that checks if a value is within the range specified by min and max,
+// including the boundary values. By calling Exclusive, the rule will exclude the boundary values.
+// The values are compared in the same way as with Min and Max.
+// An empty value is considered valid. Please use the Required rule to make sure a value is not empty.
+func Between(min, max interface{}) RangeRule {
+	return RangeRule{
+		min: Min(min),
+		max: Max(max),
+		err: ErrBetweenRequired,
+	}
+}
+
+// Exclusive sets the comparison to exclude the boundary values.
+func (r RangeRule) Exclusive() RangeRule {
+	r.min = r.min.Exclusive()
+	r.max = r.max.Exclusive()
+	r.err = ErrBetweenExclusiveRequired
+	return r
+}
+
+// Coerce enables numeric coercion. Please refer to ThresholdRule.Coerce for details.
+func (r RangeRule) Coerce() RangeRule {
+	r.min = r.min.Coerce()
+	r.max = r.max.Coerce()
+	return r
+}
+
+// Validate checks if the given value is valid or not.
+func (r RangeRule) Validate(value interface{}) error {
+	return r.validate(r.min.Validate(value), r.max.Validate(value))
+}
+
+// ValidateWithContext checks if the given value is valid or not.
+// Numeric coercion is enabled if the context is created by WithCoercion.
+func (r RangeRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	return r.validate(r.min.ValidateWithContext(ctx, value), r.max.ValidateWithContext(ctx, value))
+}
+
+func (r RangeRule) validate(minErr, maxErr error) error {
+	for _, err := range []error{minErr, maxErr} {
+		if err == nil {
+			continue
+		}
+		if _, ok := err.(Error); !ok {
+			return err
+		}
+		return r.err.SetParams(map[string]interface{}{"min": r.min.threshold, "max": r.max.threshold})
+	}
+	return nil
+}
+
+// Error sets the error message for the rule.
+func (r RangeRule) Error(message string) RangeRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r RangeRule) ErrorObject(err Error) RangeRule {
+	r.err = err
+	return r
+}
+
 func (r ThresholdRule) compareInt(threshold, value int64) bool {
 	switch r.operator {
 	case greaterThan:
@@ -181,6 +317,19 @@ func (r ThresholdRule) compareFloat(threshold, value float64) bool {
 	}
 }
 
+func (r ThresholdRule) compareOrder(c int) bool {
+	switch r.operator {
+	case greaterThan:
+		return c > 0
+	case greaterEqualThan:
+		return c >= 0
+	case lessThan:
+		return c < 0
+	default:
+		return c <= 0
+	}
+}
+
 func (r ThresholdRule) compareTime(threshold, value time.Time) bool {
 	switch r.operator {
 	case greaterThan:
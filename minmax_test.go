@@ -5,6 +5,9 @@
 package validation
 
 import (
+	"context"
+	"encoding/json"
+	"math"
 	"testing"
 	"time"
 
@@ -155,3 +158,60 @@ func TestThresholdRule_ErrorObject(t *testing.T) {
 	assert.Equal(t, err.Code(), r.err.Code())
 	assert.Equal(t, err.Message(), r.err.Message())
 }
+
+func TestThresholdRule_Coerce(t *testing.T) {
+	date20000601 := time.Date(2000, 6, 1, 0, 0, 0, 0, time.UTC)
+	big := "123456789012345678901234567890"
+
+	tests := []struct {
+		tag   string
+		rule  ThresholdRule
+		value interface{}
+		err   string
+	}{
+		{"t1.1", Min(18), float64(18), ""},
+		{"t1.2", Min(18), float64(17.5), "must be no less than 18"},
+		{"t1.3", Min(18), json.Number("18"), ""},
+		{"t1.4", Min(18), "17", "must be no less than 18"},
+		{"t1.5", Min(18), "18.5", ""},
+		{"t1.6", Min(18), "1e2", ""},
+		{"t1.7", Min(18), uint8(20), ""},
+		{"t1.8", Min(18).Exclusive(), int64(18), "must be greater than 18"},
+		{"t1.9", Min(18), "abc", "must be a valid number"},
+		{"t1.10", Min(18), "", ""},
+		{"t1.11", Min(18), "1e100000", "must be a valid number"},
+		{"t1.12", Min(18), true, "cannot convert bool to a number"},
+		{"t1.13", Min(18), nil, ""},
+		// uint64 values beyond the int64 range are compared without overflow
+		{"t2.1", Max(int64(-1)), uint64(math.MaxUint64), "must be no greater than -1"},
+		{"t2.2", Min(uint64(math.MaxUint64)), int64(-1), "must be no less than 18446744073709551615"},
+		{"t2.3", Max(uint64(math.MaxUint64)), big, "must be no greater than 18446744073709551615"},
+		{"t2.4", Min(int64(math.MaxInt64)), "9223372036854775808", ""},
+		// floats are compared exactly by their shortest decimal representations
+		{"t3.1", Min(0.1), "0.1", ""},
+		{"t3.2", Max(float32(1.5)), 2, "must be no greater than 1.5"},
+		{"t3.3", Max(1.0), "1e400", "must be no greater than 1"},
+		{"t3.4", Min(0.0), math.NaN(), "cannot compare NaN"},
+		{"t3.5", Max(math.Inf(1)), big, ""},
+		{"t3.6", Min(int64(9007199254740993)), float64(9007199254740992), "must be no less than 9007199254740993"},
+		{"t3.7", Max(uint64(math.MaxUint64)), float64(math.MaxUint64), "must be no greater than 18446744073709551615"},
+		{"t3.8", Min(float32(0.1)), "0.1", ""},
+		{"t3.9", Min(math.Inf(-1)), "-1e400", ""},
+		{"t3.10", Max(1e308), "1e400", "must be no greater than 1e+308"},
+		// thresholds
+		{"t4.1", Min("10"), 11, ""},
+		{"t4.2", Min(struct{}{}), 11, "type not supported: struct {}"},
+		{"t4.3", Min(date20000601), date20000601, ""},
+		{"t4.4", Min(date20000601), "2000", "cannot convert string to time.Time"},
+	}
+
+	for _, test := range tests {
+		err := test.rule.Coerce().Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	// coercion is disabled by default
+	assert.EqualError(t, Min(18).Validate(float64(18)), "cannot convert float64 to int64")
+	assert.EqualError(t, Min(18).ValidateWithContext(context.Background(), float64(18)), "cannot convert float64 to int64")
+	assert.Nil(t, Min(18).ValidateWithContext(WithCoercion(context.Background()), float64(18)))
+}
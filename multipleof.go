@@ -1,7 +1,11 @@
 package validation
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
 )
 
@@ -9,7 +13,10 @@ import (
 var ErrMultipleOfInvalid = NewError("validation_multiple_of_invalid", "must be multiple of {{.base}}")
 
 // MultipleOf returns a validation rule that checks if a value is a multiple of the "base" value.
-// Note that "base" should be of integer type.
+// The "base" value may be of an int, uint or float type, a *big.Int, *big.Rat or *big.Float,
+// or a custom numeric type that declares a method `Rat() *big.Rat`. The value being checked should be
+// of the same kind as the base, unless either of them is of a math/big or custom type, or numeric coercion
+// is enabled by calling Coerce. A float value is checked within a tolerance (see Tolerance).
 func MultipleOf(base interface{}) MultipleOfRule {
 	return MultipleOfRule{
 		base: base,
@@ -19,8 +26,11 @@ func MultipleOf(base interface{}) MultipleOfRule {
 
 // MultipleOfRule is a validation rule that checks if a value is a multiple of the "base" value.
 type MultipleOfRule struct {
-	base interface{}
-	err  Error
+	base         interface{}
+	err          Error
+	coerce       bool
+	tolerance    float64
+	hasTolerance bool
 }
 
 // Error sets the error message for the rule.
@@ -35,8 +45,31 @@ func (r MultipleOfRule) ErrorObject(err Error) MultipleOfRule {
 	return r
 }
 
+// Coerce enables numeric coercion. The value being checked and the base may then be of any int, uint
+// or float type, a json.Number or a string in decimal notation, and they are checked by value.
+// For example, 18.0 and "18" are multiples of 3, and "0.75" is a multiple of "0.25".
+// A string that is not a number results in ErrNotNumeric, and an empty string is considered valid.
+func (r MultipleOfRule) Coerce() MultipleOfRule {
+	r.coerce = true
+	return r
+}
+
+// Tolerance sets the maximum difference between the value and the nearest multiple of the base
+// for the value to be considered a multiple. If either of them is a float, the tolerance defaults
+// to one billionth of the base, so that 0.3 is a multiple of 0.1 despite rounding errors.
+// Otherwise, the check is exact unless a tolerance is set.
+func (r MultipleOfRule) Tolerance(tolerance float64) MultipleOfRule {
+	r.tolerance = math.Abs(tolerance)
+	r.hasTolerance = true
+	return r
+}
+
 // Validate checks if the value is a multiple of the "base" value.
 func (r MultipleOfRule) Validate(value interface{}) error {
+	if r.coerce || isCustomNumber(value) {
+		return r.validateNumber(value, r.coerce)
+	}
+
 	rv := reflect.ValueOf(r.base)
 	switch rv.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -57,9 +90,103 @@ func (r MultipleOfRule) Validate(value interface{}) error {
 		if v%rv.Uint() == 0 {
 			return nil
 		}
+
+	case reflect.Float32, reflect.Float64:
+		v, err := ToFloat(value)
+		if err != nil {
+			return err
+		}
+		if r.isFloatMultiple(v, rv.Float()) {
+			return nil
+		}
+
+	case reflect.Ptr, reflect.Struct:
+		return r.validateNumber(value, false)
+
 	default:
 		return fmt.Errorf("type not supported: %v", rv.Type())
 	}
 
 	return r.err.SetParams(map[string]interface{}{"base": r.base})
 }
+
+// ValidateWithContext checks if the value is a multiple of the "base" value.
+// Numeric coercion is enabled if the context is created by WithCoercion.
+func (r MultipleOfRule) ValidateWithContext(ctx context.Context, value interface{}) error {
+	if coercionEnabled(ctx) {
+		r.coerce = true
+	}
+	return r.Validate(value)
+}
+
+// validateNumber checks if the value is a multiple of the "base" value by their values.
+// If coerce is true, numeric strings are accepted too.
+func (r MultipleOfRule) validateNumber(value interface{}, coerce bool) error {
+	value, isNil := Indirect(value)
+	if isNil || coerce && isEmptyString(value) {
+		return nil
+	}
+	base, err := toNumber(r.base, coerce)
+	if err != nil || base.rat == nil {
+		return fmt.Errorf("type not supported: %v", reflect.TypeOf(r.base))
+	}
+	if base.rat.Sign() == 0 {
+		return errors.New("base cannot be zero")
+	}
+	v, err := toNumber(value, coerce)
+	if err != nil {
+		return err
+	}
+	if v.rat == nil {
+		return fmt.Errorf("cannot check if %v is a multiple", v.float)
+	}
+	if v.isFloat || base.isFloat {
+		if r.isFloatMultiple(v.toFloat(), base.toFloat()) {
+			return nil
+		}
+	} else if r.isRatMultiple(v.rat, base.rat) {
+		return nil
+	}
+	return r.err.SetParams(map[string]interface{}{"base": r.base})
+}
+
+// isFloatMultiple checks if a float value is a multiple of a float base within the tolerance.
+func (r MultipleOfRule) isFloatMultiple(value, base float64) bool {
+	if base == 0 {
+		return value == 0
+	}
+	n := math.Round(value / base)
+	if math.IsInf(n, 0) || math.IsNaN(n) {
+		return false
+	}
+	tolerance := math.Abs(base) * 1e-9
+	if r.hasTolerance {
+		tolerance = r.tolerance
+	}
+	return math.Abs(value-n*base) <= tolerance
+}
+
+// isRatMultiple checks if an exact value is a multiple of an exact base within the tolerance.
+func (r MultipleOfRule) isRatMultiple(value, base *big.Rat) bool {
+	q := new(big.Rat).Quo(value, base)
+	if q.IsInt() {
+		return true
+	}
+	if !r.hasTolerance || r.tolerance == 0 {
+		return false
+	}
+	if math.IsInf(r.tolerance, 0) {
+		return true
+	}
+	// round the quotient to the nearest integer and compare the difference with the tolerance
+	half := new(big.Rat).SetFrac64(1, 2)
+	if q.Sign() < 0 {
+		half.Neg(half)
+	}
+	q.Add(q, half)
+	n := new(big.Int).Quo(q.Num(), q.Denom())
+	diff := new(big.Rat).Mul(new(big.Rat).SetInt(n), base)
+	diff.Sub(value, diff)
+	diff.Abs(diff)
+	return diff.Cmp(new(big.Rat).SetFloat64(r.tolerance)) <= 0
+}
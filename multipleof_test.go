@@ -5,6 +5,10 @@
 package validation
 
 import (
+	"context"
+	"encoding/json"
+	"math"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,3 +46,76 @@ func TestMultipleOfRule_ErrorObject(t *testing.T) {
 	assert.Equal(t, err.Code(), r.err.Code())
 	assert.Equal(t, err.Message(), r.err.Message())
 }
+
+func TestMultipleOfRule_Coerce(t *testing.T) {
+	tests := []struct {
+		tag   string
+		base  interface{}
+		value interface{}
+		err   string
+	}{
+		{"t1", 3, float64(18), ""},
+		{"t2", 3, float64(18.5), "must be multiple of 3"},
+		{"t3", 3, json.Number("18"), ""},
+		{"t4", uint(3), "-9", ""},
+		{"t5", "0.25", "0.75", ""},
+		{"t6", "0.25", "0.8", "must be multiple of 0.25"},
+		{"t7", 3, "123456789012345678901234567890", ""},
+		{"t8", 3, "x", "must be a valid number"},
+		{"t9", 3, "", ""},
+		{"t10", 3, nil, ""},
+		{"t11", 0, 3, "base cannot be zero"},
+		{"t12", math.Inf(1), 3, "type not supported: float64"},
+		{"t13", 3, math.NaN(), "cannot check if NaN is a multiple"},
+	}
+
+	for _, test := range tests {
+		err := MultipleOf(test.base).Coerce().Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	assert.EqualError(t, MultipleOf(3).ValidateWithContext(context.Background(), float64(18)), "cannot convert float64 to int64")
+	assert.Nil(t, MultipleOf(3).ValidateWithContext(WithCoercion(context.Background()), float64(18)))
+}
+
+func TestMultipleOfRule_FloatAndBig(t *testing.T) {
+	tests := []struct {
+		tag   string
+		rule  MultipleOfRule
+		value interface{}
+		err   string
+	}{
+		{"t1.1", MultipleOf(0.1), 0.3, ""},
+		{"t1.2", MultipleOf(0.1), 0.35, "must be multiple of 0.1"},
+		{"t1.3", MultipleOf(0.1), 3, "cannot convert int to float64"},
+		{"t1.4", MultipleOf(0.1).Coerce(), "0.3", ""},
+		{"t1.5", MultipleOf(0.5).Tolerance(0.1), 1.05, ""},
+		{"t1.6", MultipleOf(0.5).Tolerance(0), 1.0000001, "must be multiple of 0.5"},
+		{"t1.7", MultipleOf(0.5), -1.5, ""},
+		{"t1.8", MultipleOf(1e-300), 1e300, "must be multiple of 1e-300"},
+		{"t1.9", MultipleOf(0.0), 0.0, ""},
+		{"t2.1", MultipleOf(big.NewInt(3)), big.NewInt(9), ""},
+		{"t2.2", MultipleOf(big.NewInt(3)), 10, "must be multiple of 3"},
+		{"t2.3", MultipleOf(big.NewRat(1, 4)), 0.75, ""},
+		{"t2.4", MultipleOf(big.NewRat(1, 3)), big.NewRat(2, 3), ""},
+		{"t2.5", MultipleOf(big.NewRat(1, 3)), "1", "cannot convert string to a number"},
+		{"t2.6", MultipleOf(big.NewRat(1, 3)).Tolerance(0.001), big.NewRat(333, 1000), ""},
+		{"t2.7", MultipleOf(big.NewRat(1, 3)).Tolerance(0.001), big.NewRat(-1999, 3000), ""},
+		{"t2.8", MultipleOf(big.NewRat(1, 3)).Tolerance(0.001), big.NewRat(1, 2), "must be multiple of 1/3"},
+		{"t2.9", MultipleOf(big.NewInt(3)).Tolerance(math.Inf(1)), 10, ""},
+		{"t2.10", MultipleOf(3), big.NewInt(5), "must be multiple of 3"},
+		{"t2.11", MultipleOf(3), big.NewInt(9), ""},
+		{"t2.12", MultipleOf(0.25), big.NewRat(3, 4), ""},
+		{"t2.13", MultipleOf(uint(2)), big.NewFloat(3), "must be multiple of 2"},
+		{"t3.1", MultipleOf(money{25}), money{75}, ""},
+		{"t3.2", MultipleOf(money{25}), money{80}, "must be multiple of 0.25"},
+		{"t3.3", MultipleOf(money{25}), 1.5, ""},
+		{"t3.4", MultipleOf(money{0}), 1, "base cannot be zero"},
+		{"t3.5", MultipleOf(struct{}{}), 1, "type not supported: struct {}"},
+	}
+
+	for _, test := range tests {
+		err := test.rule.Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+	}
+}
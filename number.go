@@ -0,0 +1,241 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// Comparable is the interface that custom numeric types, such as decimals, may implement so that their values
+// can be checked by Min, Max and Between. Cmp compares the value with other, and returns -1, 0 or +1
+// if the value is less than, equal to or greater than other. If the rule's parameter is a pointer to a value
+// of the same type as the value being checked, other is the referenced value.
+//
+// Types that do not implement Comparable but declare a method `Cmp(T) int`, where T is the type itself or
+// a pointer to it, are supported as well. This covers most third-party decimal types. Types that declare
+// a method `Rat() *big.Rat` can also be checked by MultipleOf and compared with values of other numeric types.
+type Comparable interface {
+	Cmp(other interface{}) int
+}
+
+type (
+	// number is a numeric value obtained through conversion or coercion.
+	number struct {
+		// rat is the exact value. The value of a Go floating-point number is the value of its shortest decimal
+		// representation. It is nil for NaN and infinite floating-point values.
+		rat *big.Rat
+		// isFloat indicates the value is a Go floating-point number.
+		isFloat bool
+		// float is the value of a Go floating-point number.
+		float float64
+	}
+
+	// rational is implemented by custom numeric types that can be converted to an exact rational number.
+	rational interface {
+		Rat() *big.Rat
+	}
+)
+
+var (
+	comparableType = reflect.TypeOf((*Comparable)(nil)).Elem()
+)
+
+// toNumber converts a value into a number. Any integer, unsigned integer or floating-point kind is accepted,
+// as well as *big.Int, *big.Rat, *big.Float and types implementing `Rat() *big.Rat`.
+// If coerce is true, json.Number and strings in decimal notation are accepted too.
+func toNumber(value interface{}, coerce bool) (number, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return number{rat: new(big.Rat).SetInt64(v.Int())}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return number{rat: new(big.Rat).SetInt(new(big.Int).SetUint64(v.Uint()))}, nil
+	case reflect.Float32, reflect.Float64:
+		return floatNumber(v.Float(), v.Type().Bits()), nil
+	case reflect.String:
+		if coerce {
+			rat, err := parseNumericString(v.String())
+			if err != nil {
+				return number{}, err
+			}
+			return number{rat: rat}, nil
+		}
+	case reflect.Ptr, reflect.Struct:
+		if n, ok := bigNumber(v); ok {
+			return n, nil
+		}
+	}
+	return number{}, fmt.Errorf("cannot convert %v to a number", reflect.TypeOf(value))
+}
+
+// bigNumber converts a value of a math/big type or a type implementing `Rat() *big.Rat` into a number.
+func bigNumber(v reflect.Value) (number, bool) {
+	if v.Kind() != reflect.Ptr {
+		v = addressOf(v)
+	} else if v.IsNil() {
+		return number{}, false
+	}
+	switch x := v.Interface().(type) {
+	case *big.Int:
+		return number{rat: new(big.Rat).SetInt(x)}, true
+	case *big.Rat:
+		return number{rat: new(big.Rat).Set(x)}, true
+	case *big.Float:
+		if x.IsInf() {
+			return floatNumber(math.Inf(x.Sign()), 64), true
+		}
+		r, _ := x.Rat(nil)
+		return number{rat: r}, true
+	case rational:
+		if r := x.Rat(); r != nil {
+			return number{rat: r}, true
+		}
+	}
+	return number{}, false
+}
+
+// isCustomNumber checks if a value is of a math/big type or a custom numeric type, which is compared by value
+// with numbers of any type.
+func isCustomNumber(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	if k := v.Kind(); k != reflect.Ptr && k != reflect.Struct {
+		return false
+	}
+	if _, ok := bigNumber(v); ok {
+		return true
+	}
+	return hasCmpMethod(v.Type())
+}
+
+// floatNumber returns the number of a Go floating-point value of the given bit size. The exact value of the number
+// is the value of the shortest decimal representation of the float, so that 0.1 equals the decimal 0.1.
+func floatNumber(f float64, bitSize int) number {
+	n := number{isFloat: true, float: f}
+	if !math.IsNaN(f) && !math.IsInf(f, 0) {
+		n.rat, _ = new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, bitSize))
+	}
+	return n
+}
+
+// compareNumbers compares two numbers by value and returns -1, 0 or +1.
+// Finite numbers are compared exactly, including floating-point numbers, which are compared by the values of
+// their shortest decimal representations. An infinite number is greater or less than any finite number.
+// An error is returned if either number is NaN.
+func compareNumbers(a, b number) (int, error) {
+	if a.rat != nil && b.rat != nil {
+		return a.rat.Cmp(b.rat), nil
+	}
+	af, bf := a.toFloat(), b.toFloat()
+	if math.IsNaN(af) || math.IsNaN(bf) {
+		return 0, errors.New("cannot compare NaN")
+	}
+	// a finite number that is out of the float64 range is still less than +Inf and greater than -Inf
+	if a.rat != nil {
+		af = 0
+	} else if b.rat != nil {
+		bf = 0
+	}
+	switch {
+	case af < bf:
+		return -1, nil
+	case af > bf:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// toFloat returns the nearest float64 value of the number.
+func (n number) toFloat() float64 {
+	if n.isFloat {
+		return n.float
+	}
+	f, _ := n.rat.Float64()
+	return f
+}
+
+// compareValues compares a value with a threshold by value and returns -1, 0 or +1.
+// Numbers are compared using compareNumbers. Other values are compared using their Cmp method.
+func compareValues(value, threshold interface{}, coerce bool) (int, error) {
+	if t, err := toNumber(threshold, coerce); err == nil {
+		if v, err := toNumber(value, coerce); err == nil {
+			return compareNumbers(v, t)
+		}
+	}
+	if c, ok := compareByMethod(value, threshold); ok {
+		return c, nil
+	}
+	if hasCmpMethod(reflect.TypeOf(threshold)) {
+		return 0, fmt.Errorf("cannot convert %v to %v", reflect.TypeOf(value), reflect.TypeOf(threshold))
+	}
+	if _, err := toNumber(threshold, coerce); err == nil {
+		_, err = toNumber(value, coerce)
+		return 0, err
+	}
+	return 0, fmt.Errorf("type not supported: %v", reflect.TypeOf(threshold))
+}
+
+// compareByMethod compares a value with other by calling the value's Cmp method.
+// The boolean result indicates whether the method is found and accepts other.
+func compareByMethod(value, other interface{}) (int, bool) {
+	v := reflect.ValueOf(value)
+	o := reflect.ValueOf(other)
+	if !v.IsValid() || !o.IsValid() || !hasCmpMethod(v.Type()) {
+		return 0, false
+	}
+	t := indirectType(v.Type())
+	if v.Kind() != reflect.Ptr {
+		// use a pointer so that methods declared with a pointer receiver are found
+		v = addressOf(v)
+	} else if v.IsNil() {
+		return 0, false
+	}
+	if o.Kind() == reflect.Ptr && !o.IsNil() && o.Type().Elem() == t {
+		o = o.Elem()
+	}
+	if c, ok := v.Interface().(Comparable); ok {
+		return c.Cmp(o.Interface()), true
+	}
+	m := v.MethodByName("Cmp")
+	switch in := m.Type().In(0); {
+	case o.Type() == in:
+	case reflect.PtrTo(o.Type()) == in:
+		o = addressOf(o)
+	default:
+		return 0, false
+	}
+	return int(m.Call([]reflect.Value{o})[0].Int()), true
+}
+
+// hasCmpMethod checks if a type implements Comparable or has a method `Cmp(T) int`, where T is the type
+// itself or a pointer to it. Pointer types and methods declared with a pointer receiver are handled likewise.
+func hasCmpMethod(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	t = indirectType(t)
+	pt := reflect.PtrTo(t)
+	if pt.Implements(comparableType) {
+		return true
+	}
+	m, ok := pt.MethodByName("Cmp")
+	if !ok {
+		return false
+	}
+	mt := m.Type
+	return mt.NumIn() == 2 && mt.NumOut() == 1 && mt.Out(0).Kind() == reflect.Int && indirectType(mt.In(1)) == t
+}
+
+// indirectType returns the type that a pointer type points to, or the type itself if it is not a pointer.
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,165 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// money is a fixed-point decimal with two fraction digits that has a typed Cmp method and a Rat method.
+type money struct {
+	cents int64
+}
+
+func (m money) Cmp(other money) int {
+	switch {
+	case m.cents < other.cents:
+		return -1
+	case m.cents > other.cents:
+		return 1
+	}
+	return 0
+}
+
+func (m money) Rat() *big.Rat {
+	return big.NewRat(m.cents, 100)
+}
+
+func (m money) String() string {
+	return fmt.Sprintf("%d.%02d", m.cents/100, m.cents%100)
+}
+
+// version implements Comparable with a pointer receiver.
+type version struct {
+	major, minor int
+}
+
+func (v *version) Cmp(other interface{}) int {
+	o := other.(version)
+	if v.major != o.major {
+		return v.major - o.major
+	}
+	return v.minor - o.minor
+}
+
+// opaque has a typed Cmp method with a pointer receiver and cannot be converted to a number.
+type opaque struct {
+	v int
+}
+
+func (o *opaque) Cmp(other *opaque) int {
+	return o.v - other.v
+}
+
+func TestThresholdRule_BigAndCustomTypes(t *testing.T) {
+	tests := []struct {
+		tag   string
+		rule  ThresholdRule
+		value interface{}
+		err   string
+	}{
+		{"t1.1", Min(big.NewInt(10)), big.NewInt(10), ""},
+		{"t1.2", Min(big.NewInt(10)), big.NewInt(9), "must be no less than 10"},
+		{"t1.3", Min(big.NewInt(10)), 11, ""},
+		{"t1.4", Max(big.NewInt(10)), uint64(math.MaxUint64), "must be no greater than 10"},
+		{"t1.5", Max(big.NewRat(1, 3)), 0.5, "must be no greater than 1/3"},
+		{"t1.6", Min(big.NewRat(1, 3)), big.NewRat(2, 3), ""},
+		{"t1.7", Min(big.NewFloat(1.5)).Exclusive(), big.NewFloat(1.5), "must be greater than 1.5"},
+		{"t1.8", Max(new(big.Float).SetInf(false)), big.NewInt(1), ""},
+		{"t1.9", Min(big.NewInt(10)), "11", "cannot convert string to *big.Int"},
+		{"t1.10", Min(big.NewInt(10)).Coerce(), "11", ""},
+		{"t1.11", Min(big.NewInt(10)), (*big.Int)(nil), ""},
+		{"t1.12", Min(10), big.NewInt(11), ""},
+		{"t1.13", Min(0), big.NewInt(-5), "must be no less than 0"},
+		{"t1.14", Max(1.5), big.NewRat(3, 1), "must be no greater than 1.5"},
+		{"t1.15", Max(uint(10)), big.NewFloat(9.5), ""},
+		{"t1.16", Min(10), struct{ v int }{11}, "cannot convert struct to int64"},
+		{"t2.5", Min(1), money{150}, ""},
+		{"t2.6", Max(1.25), money{150}, "must be no greater than 1.25"},
+		{"t2.1", Min(money{150}), money{150}, ""},
+		{"t2.2", Min(money{150}), money{149}, "must be no less than 1.50"},
+		{"t2.3", Min(money{150}), 1.49, "must be no less than 1.50"},
+		{"t2.4", Max(money{150}), big.NewRat(3, 2), ""},
+		{"t3.1", Min(&version{1, 2}), version{1, 2}, ""},
+		{"t3.2", Min(version{1, 2}), &version{1, 1}, "must be no less than {1 2}"},
+		{"t3.3", Max(version{1, 2}), 1, "cannot convert int to validation.version"},
+		{"t4.1", Min(&opaque{2}), opaque{3}, ""},
+		{"t4.2", Min(opaque{2}), &opaque{1}, "must be no less than {2}"},
+		{"t4.3", Min(opaque{2}), "x", "cannot convert string to validation.opaque"},
+		{"t5.1", Min(struct{}{}), 1, "type not supported: struct {}"},
+	}
+
+	for _, test := range tests {
+		err := test.rule.Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		tag   string
+		rule  RangeRule
+		value interface{}
+		err   string
+	}{
+		{"t1", Between(1, 3), 1, ""},
+		{"t2", Between(1, 3), 3, ""},
+		{"t3", Between(1, 3), 0, "must be between 1 and 3"},
+		{"t4", Between(1, 3), 4, "must be between 1 and 3"},
+		{"t5", Between(1, 3).Exclusive(), 3, "must be greater than 1 and less than 3"},
+		{"t6", Between(1, 3), "2", "cannot convert string to int64"},
+		{"t7", Between(1, 3).Coerce(), "2", ""},
+		{"t8", Between(1, 3), nil, ""},
+		{"t9", Between(time.Second, time.Minute), 90 * time.Second, "must be between 1s and 1m0s"},
+		{"t10", Between(big.NewInt(1), money{300}), money{250}, ""},
+		{"t11", Between(1, 3).Error("out of range"), 4, "out of range"},
+		{"t12", Between(1, 3).ErrorObject(NewError("code", "abc")), 4, "abc"},
+	}
+
+	for _, test := range tests {
+		err := test.rule.Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	assert.Nil(t, Between(1, 3).ValidateWithContext(WithCoercion(context.Background()), 2.5))
+}
+
+func TestThresholdRule_Duration(t *testing.T) {
+	r := Max(90 * time.Second)
+	assert.EqualError(t, r.Validate(2*time.Minute), "must be no greater than 1m30s")
+	assert.EqualError(t, r.Coerce().Validate("2m"), "must be no greater than 1m30s")
+	assert.Nil(t, r.Coerce().Validate("1m"))
+	assert.Nil(t, r.Coerce().Validate("1000"))
+}
+
+func TestCompareByMethod(t *testing.T) {
+	c, ok := compareByMethod(money{1}, money{2})
+	assert.True(t, ok)
+	assert.Equal(t, -1, c)
+	c, ok = compareByMethod(&opaque{3}, opaque{1})
+	assert.True(t, ok)
+	assert.Equal(t, 2, c)
+	_, ok = compareByMethod(money{1}, 2)
+	assert.False(t, ok)
+	_, ok = compareByMethod(1, 2)
+	assert.False(t, ok)
+	_, ok = compareByMethod((*opaque)(nil), opaque{1})
+	assert.False(t, ok)
+	_, ok = compareByMethod(nil, 1)
+	assert.False(t, ok)
+
+	assert.True(t, hasCmpMethod(reflect.TypeOf(big.NewInt(1))))
+	assert.True(t, hasCmpMethod(reflect.TypeOf(version{})))
+	assert.False(t, hasCmpMethod(reflect.TypeOf(time.Time{})))
+	assert.False(t, hasCmpMethod(nil))
+}
@@ -0,0 +1,141 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+type (
+	// Optional holds a value that may be omitted in the input. When a struct is decoded from JSON,
+	// Present is set if the field appears in the input. A JSON null is treated as a present zero value;
+	// use Nullable to tell null apart from other values.
+	//
+	// Indirect treats an Optional that is not present as nil and otherwise returns the inner value,
+	// so rules such as Required, Length and Min apply to the inner value. Use the Present and Absent
+	// rules to check the presence of the value.
+	Optional[T any] struct {
+		// V is the value.
+		V T
+		// Present indicates the value is provided.
+		Present bool
+	}
+
+	// Nullable holds a value that may be omitted or explicitly set to null in the input. When a struct is
+	// decoded from JSON, Present is set if the field appears in the input, and Valid is set if the value is not null.
+	//
+	// Indirect treats a Nullable that is not present or is null as nil and otherwise returns the inner value,
+	// so rules such as Required, Length and Min apply to the inner value. Use the Present, NotNull and
+	// Absent rules to tell omitted values from null values.
+	Nullable[T any] struct {
+		// V is the value. It is the zero value if the value is null.
+		V T
+		// Present indicates the value is provided, either as null or as a value.
+		Present bool
+		// Valid indicates the value is not null.
+		Valid bool
+	}
+
+	// presenceTracker is implemented by the types that track the presence of their values.
+	presenceTracker interface {
+		// presence returns whether the value is present and whether it is null.
+		presence() (present, null bool)
+		// innerValue returns the value being held.
+		innerValue() interface{}
+	}
+)
+
+var presenceTrackerType = reflect.TypeOf((*presenceTracker)(nil)).Elem()
+
+// UnmarshalJSON decodes the value from JSON and marks it as present.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Present = true
+	return json.Unmarshal(data, &o.V)
+}
+
+// MarshalJSON encodes the value into JSON. A value that is not present is encoded as null.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Present {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.V)
+}
+
+// IsZero reports whether the value is not present. It allows the field to be omitted by the omitzero JSON option.
+func (o Optional[T]) IsZero() bool {
+	return !o.Present
+}
+
+// IsEmpty reports whether the value is not present or the inner value is empty. It implements Emptier,
+// so that IsEmpty and the rules based on it, such as Required, treat a present zero value as empty.
+func (o Optional[T]) IsEmpty() bool {
+	return !o.Present || IsEmpty(o.V)
+}
+
+func (o Optional[T]) presence() (bool, bool) {
+	return o.Present, false
+}
+
+func (o Optional[T]) innerValue() interface{} {
+	return o.V
+}
+
+// UnmarshalJSON decodes the value from JSON and marks it as present. A JSON null marks the value as null.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Present = true
+	if string(data) == "null" {
+		var zero T
+		n.V, n.Valid = zero, false
+		return nil
+	}
+	if err := json.Unmarshal(data, &n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// MarshalJSON encodes the value into JSON. A value that is not present or is null is encoded as null.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Present || !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+// IsZero reports whether the value is not present. It allows the field to be omitted by the omitzero JSON option.
+func (n Nullable[T]) IsZero() bool {
+	return !n.Present
+}
+
+// IsEmpty reports whether the value is not present, is null or the inner value is empty. It implements Emptier,
+// so that IsEmpty and the rules based on it, such as Required, treat a present zero value as empty.
+func (n Nullable[T]) IsEmpty() bool {
+	return !n.Present || !n.Valid || IsEmpty(n.V)
+}
+
+func (n Nullable[T]) presence() (bool, bool) {
+	return n.Present, n.Present && !n.Valid
+}
+
+func (n Nullable[T]) innerValue() interface{} {
+	return n.V
+}
+
+// indirectPresence returns the presence tracker held by the given value directly or through pointers.
+func indirectPresence(value interface{}) (presenceTracker, bool) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || !rv.Type().Implements(presenceTrackerType) {
+		return nil, false
+	}
+	return rv.Interface().(presenceTracker), true
+}
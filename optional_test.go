@@ -0,0 +1,144 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type patchRequest struct {
+	Name     Optional[string]       `json:"name"`
+	Nickname Nullable[string]       `json:"nickname"`
+	Age      Nullable[int]          `json:"age"`
+	Address  Optional[deepAddress]  `json:"address"`
+	Tags     *Optional[[]string]    `json:"tags"`
+	Extra    Nullable[*deepAddress] `json:"extra"`
+}
+
+func (r *patchRequest) Validate() error {
+	return ValidateStruct(r,
+		Field(&r.Name, NotNull, Length(2, 10)),
+		Field(&r.Nickname, Present, Length(2, 10)),
+		Field(&r.Age, NotNull, Min(18)),
+		Field(&r.Address),
+		Field(&r.Tags, Absent),
+		Field(&r.Extra),
+	)
+}
+
+func TestOptional_UnmarshalJSON(t *testing.T) {
+	var r patchRequest
+	err := json.Unmarshal([]byte(`{"name": "ab", "nickname": null, "age": 20, "address": {"city": "x"}}`), &r)
+	assert.Nil(t, err)
+	assert.Equal(t, Optional[string]{V: "ab", Present: true}, r.Name)
+	assert.Equal(t, Nullable[string]{Present: true}, r.Nickname)
+	assert.Equal(t, Nullable[int]{V: 20, Present: true, Valid: true}, r.Age)
+	assert.Equal(t, Optional[deepAddress]{V: deepAddress{City: "x"}, Present: true}, r.Address)
+	assert.Nil(t, r.Tags)
+	assert.Equal(t, Nullable[*deepAddress]{}, r.Extra)
+
+	r = patchRequest{}
+	err = json.Unmarshal([]byte(`{"name": null}`), &r)
+	assert.Nil(t, err)
+	assert.Equal(t, Optional[string]{Present: true}, r.Name)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"age": "x"}`), &r))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"name": 1}`), &r))
+}
+
+func TestOptional_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		A Optional[int]    `json:"a"`
+		B Optional[int]    `json:"b"`
+		C Nullable[string] `json:"c"`
+		D Nullable[string] `json:"d"`
+		E Nullable[string] `json:"e,omitzero"`
+	}{
+		A: Optional[int]{V: 1, Present: true},
+		C: Nullable[string]{Present: true},
+		D: Nullable[string]{V: "x", Present: true, Valid: true},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":1,"b":null,"c":null,"d":"x"}`, string(data))
+}
+
+func TestOptional_Validate(t *testing.T) {
+	tests := []struct {
+		tag  string
+		json string
+		err  string
+	}{
+		{"t1", `{"nickname": "nick"}`, ""},
+		{"t2", `{}`, "nickname: must be provided."},
+		{"t3", `{"nickname": null, "name": null, "age": null}`, "age: cannot be null."},
+		{"t4", `{"nickname": "n", "name": "a", "age": 17}`, "age: must be no less than 18; name: the length must be between 2 and 10; nickname: the length must be between 2 and 10."},
+		{"t5", `{"nickname": null, "address": {}}`, "address: (city: cannot be blank.)."},
+		{"t6", `{"nickname": null, "tags": []}`, "tags: must not be provided."},
+		{"t7", `{"nickname": null, "extra": {}}`, "extra: (city: cannot be blank.)."},
+		{"t8", `{"nickname": null, "extra": null}`, ""},
+	}
+	for _, test := range tests {
+		var r patchRequest
+		assert.Nil(t, json.Unmarshal([]byte(test.json), &r), test.tag)
+		assertError(t, test.err, r.Validate(), test.tag)
+	}
+}
+
+func TestOptional_Indirect(t *testing.T) {
+	tests := []struct {
+		tag   string
+		value interface{}
+		want  interface{}
+		isNil bool
+	}{
+		{"t1", Optional[int]{}, nil, true},
+		{"t2", Optional[int]{V: 0, Present: true}, 0, false},
+		{"t3", &Optional[string]{V: "a", Present: true}, "a", false},
+		{"t4", Nullable[int]{Present: true}, nil, true},
+		{"t5", Nullable[int]{V: 1, Present: true, Valid: true}, 1, false},
+		{"t6", Nullable[*int]{Present: true, Valid: true}, nil, true},
+		{"t7", Optional[Nullable[int]]{V: Nullable[int]{V: 2, Present: true, Valid: true}, Present: true}, 2, false},
+	}
+	for _, test := range tests {
+		v, isNil := Indirect(test.value)
+		assert.Equal(t, test.want, v, test.tag)
+		assert.Equal(t, test.isNil, isNil, test.tag)
+	}
+
+	// the existing rules apply to the inner value
+	assert.Equal(t, ErrRequired, Required.Validate(Optional[string]{}))
+	assert.Equal(t, ErrRequired, Required.Validate(Optional[string]{Present: true}))
+	assert.Nil(t, NilOrNotEmpty.Validate(Nullable[string]{Present: true}))
+	assert.Nil(t, Nil.Validate(Nullable[string]{Present: true}))
+	assert.Equal(t, ErrNil, Nil.Validate(Nullable[string]{Present: true, Valid: true}))
+	assert.Nil(t, In("a", "b").Validate(Optional[string]{V: "a", Present: true}))
+}
+
+func TestOptional_IsEmpty(t *testing.T) {
+	tests := []struct {
+		tag   string
+		value interface{}
+		empty bool
+	}{
+		{"t1", Optional[string]{}, true},
+		{"t2", Optional[string]{Present: true}, true},
+		{"t3", Optional[string]{V: "a", Present: true}, false},
+		{"t4", &Optional[int]{V: 1, Present: true}, false},
+		{"t5", Nullable[string]{}, true},
+		{"t6", Nullable[string]{V: "a", Present: true}, true},
+		{"t7", Nullable[string]{Present: true, Valid: true}, true},
+		{"t8", Nullable[string]{V: "a", Present: true, Valid: true}, false},
+		{"t9", Optional[Nullable[int]]{V: Nullable[int]{Present: true}, Present: true}, true},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.empty, IsEmpty(test.value), test.tag)
+	}
+
+	// IsZero still reports the presence for the omitzero JSON option
+	assert.False(t, Optional[string]{Present: true}.IsZero())
+}
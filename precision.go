@@ -0,0 +1,143 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+var (
+	// ErrPrecisionDigits is the error that returns when a number has too many digits before the decimal point.
+	ErrPrecisionDigits = NewError("validation_precision_digits", "must have no more than {{.digits}} digits before the decimal point")
+	// ErrPrecisionScale is the error that returns when a number has too many decimal places.
+	ErrPrecisionScale = NewError("validation_precision_scale", "must have no more than {{.scale}} decimal places")
+)
+
+// Precision returns a validation rule that checks if a number fits a decimal column with the given precision
+// (the total number of significant digits) and scale (the number of digits after the decimal point),
+// such as NUMERIC(12, 2) in SQL. The number may have at most scale decimal places and at most
+// totalDigits-scale digits before the decimal point. Leading zeros and trailing zeros after the decimal
+// point are not counted. If totalDigits is 0 or less, only the scale is checked.
+//
+// The rule works on values of int, uint and float types, json.Number, strings in decimal notation,
+// *big.Int, *big.Rat, *big.Float and custom numeric types that declare a method `Rat() *big.Rat`.
+// A float is checked by its shortest decimal representation that converts back to the same float,
+// which is how it is formatted by strconv and encoding/json. For example, 0.1 has one decimal place,
+// although its binary value is not exactly 0.1. A *big.Rat whose decimal representation does not terminate,
+// such as 1/3, has too many decimal places for any scale.
+//
+// An empty value is considered valid. Please use the Required rule to make sure a value is not empty.
+func Precision(totalDigits, scale int) PrecisionRule {
+	return PrecisionRule{
+		digits:    totalDigits,
+		scale:     scale,
+		errDigits: ErrPrecisionDigits,
+		errScale:  ErrPrecisionScale,
+	}
+}
+
+// PrecisionRule is a validation rule that checks if a number fits the specified precision and scale.
+type PrecisionRule struct {
+	digits, scale       int
+	errDigits, errScale Error
+}
+
+// Validate checks if the given value is valid or not.
+func (r PrecisionRule) Validate(value interface{}) error {
+	value, isNil := Indirect(value)
+	if isNil || isEmptyString(value) {
+		return nil
+	}
+
+	rat, err := decimalValue(value)
+	if err != nil {
+		return err
+	}
+	intDigits, scale, ok := decimalDigits(rat)
+	if !ok || scale > r.scale {
+		return r.errScale.SetParams(map[string]interface{}{"scale": r.scale})
+	}
+	if r.digits > 0 && intDigits > r.digits-r.scale {
+		return r.errDigits.SetParams(map[string]interface{}{"digits": r.digits - r.scale})
+	}
+	return nil
+}
+
+// Error sets the error message for the rule.
+func (r PrecisionRule) Error(message string) PrecisionRule {
+	r.errDigits = r.errDigits.SetMessage(message)
+	r.errScale = r.errScale.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r PrecisionRule) ErrorObject(err Error) PrecisionRule {
+	r.errDigits = err
+	r.errScale = err
+	return r
+}
+
+// decimalValue converts a value into an exact rational number. Floats are converted from their shortest
+// decimal representation.
+func decimalValue(value interface{}) (*big.Rat, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("cannot check the precision of %v", f)
+		}
+		return parseNumericString(strconv.FormatFloat(f, 'g', -1, v.Type().Bits()))
+	case reflect.Struct:
+		if x, ok := addressOf(v).Interface().(*big.Float); ok {
+			if x.IsInf() {
+				return nil, fmt.Errorf("cannot check the precision of %v", x)
+			}
+			return parseNumericString(x.Text('g', -1))
+		}
+	}
+	n, err := toNumber(value, true)
+	if err != nil {
+		return nil, err
+	}
+	return n.rat, nil
+}
+
+// decimalDigits returns the number of digits before the decimal point and the number of decimal places
+// of an exact rational number. The boolean result is false if the decimal representation does not terminate.
+func decimalDigits(r *big.Rat) (intDigits, scale int, ok bool) {
+	// the decimal representation terminates if the denominator has no prime factors other than 2 and 5,
+	// and the number of decimal places is the larger exponent of the two factors
+	d := new(big.Int).Set(r.Denom())
+	twos := int(d.TrailingZeroBits())
+	d.Rsh(d, uint(twos))
+	fives := 0
+	five, m := big.NewInt(5), new(big.Int)
+	for {
+		q, rem := new(big.Int).QuoRem(d, five, m)
+		if rem.Sign() != 0 {
+			break
+		}
+		d = q
+		fives++
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return 0, 0, false
+	}
+	scale = twos
+	if fives > scale {
+		scale = fives
+	}
+
+	i := new(big.Int).Quo(r.Num(), r.Denom())
+	if i.Sign() != 0 {
+		intDigits = len(i.Abs(i).String())
+	}
+	return intDigits, scale, true
+}
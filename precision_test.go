@@ -0,0 +1,97 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrecision(t *testing.T) {
+	huge, _ := new(big.Int).SetString("1000000000000", 10)
+
+	tests := []struct {
+		tag   string
+		rule  PrecisionRule
+		value interface{}
+		err   string
+	}{
+		// strings and json.Number
+		{"t1.1", Precision(12, 2), "12.34", ""},
+		{"t1.2", Precision(12, 2), "12.345", "must have no more than 2 decimal places"},
+		{"t1.3", Precision(12, 2), "12.3400", ""},
+		{"t1.4", Precision(12, 2), "-0012.3", ""},
+		{"t1.5", Precision(12, 2), "9999999999.99", ""},
+		{"t1.6", Precision(12, 2), "10000000000", "must have no more than 10 digits before the decimal point"},
+		{"t1.7", Precision(12, 2), "1e15", "must have no more than 10 digits before the decimal point"},
+		{"t1.8", Precision(12, 2), "1.5e-1", ""},
+		{"t1.9", Precision(12, 2), "1.5e-2", "must have no more than 2 decimal places"},
+		{"t1.10", Precision(12, 2), json.Number("0.01"), ""},
+		{"t1.11", Precision(12, 2), "abc", "must be a valid number"},
+		{"t1.12", Precision(12, 2), "", ""},
+		// integers
+		{"t2.1", Precision(3, 0), 999, ""},
+		{"t2.2", Precision(3, 0), -1000, "must have no more than 3 digits before the decimal point"},
+		{"t2.3", Precision(3, 0), uint64(0), ""},
+		{"t2.4", Precision(0, 0), uint64(math.MaxUint64), ""},
+		// floats are checked by their shortest decimal representation
+		{"t3.1", Precision(12, 2), 0.1, ""},
+		{"t3.2", Precision(12, 2), 12.34, ""},
+		{"t3.3", Precision(12, 2), 12.345, "must have no more than 2 decimal places"},
+		{"t3.4", Precision(12, 2), float32(0.07), ""},
+		{"t3.5", Precision(12, 2), 1e15, "must have no more than 10 digits before the decimal point"},
+		{"t3.6", Precision(12, 2), math.NaN(), "cannot check the precision of NaN"},
+		{"t3.7", Precision(12, 2), math.Inf(-1), "cannot check the precision of -Inf"},
+		// math/big and custom types
+		{"t4.1", Precision(12, 2), big.NewRat(1, 4), ""},
+		{"t4.2", Precision(12, 2), big.NewRat(1, 8), "must have no more than 2 decimal places"},
+		{"t4.3", Precision(12, 6), big.NewRat(1, 3), "must have no more than 6 decimal places"},
+		{"t4.4", Precision(12, 2), huge, "must have no more than 10 digits before the decimal point"},
+		{"t4.5", Precision(12, 2), big.NewFloat(0.1), ""},
+		{"t4.6", Precision(12, 2), new(big.Float).SetInf(true), "cannot check the precision of -Inf"},
+		{"t4.7", Precision(4, 2), money{9999}, ""},
+		{"t4.8", Precision(4, 2), money{10000}, "must have no more than 2 digits before the decimal point"},
+		// others
+		{"t5.1", Precision(12, 2), nil, ""},
+		{"t5.2", Precision(12, 2), true, "cannot convert bool to a number"},
+		{"t5.3", Precision(12, 2).Error("invalid amount"), "0.001", "invalid amount"},
+		{"t5.4", Precision(12, 2).ErrorObject(NewError("code", "abc")), "1e11", "abc"},
+	}
+
+	for _, test := range tests {
+		err := test.rule.Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+	}
+}
+
+func TestDecimalDigits(t *testing.T) {
+	tests := []struct {
+		value     string
+		intDigits int
+		scale     int
+		ok        bool
+	}{
+		{"0", 0, 0, true},
+		{"0.5", 0, 1, true},
+		{"-123.125", 3, 3, true},
+		{"1/3", 0, 0, false},
+		{"7/20", 0, 2, true},
+		{"1/1024", 0, 10, true},
+		{"100", 3, 0, true},
+	}
+	for _, test := range tests {
+		r, _ := new(big.Rat).SetString(test.value)
+		intDigits, scale, ok := decimalDigits(r)
+		assert.Equal(t, test.ok, ok, test.value)
+		if test.ok {
+			assert.Equal(t, test.intDigits, intDigits, test.value)
+			assert.Equal(t, test.scale, scale, test.value)
+		}
+	}
+}
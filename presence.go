@@ -0,0 +1,110 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import "reflect"
+
+var (
+	// ErrPresentRequired is the error that returns when a value is not provided.
+	ErrPresentRequired = NewError("validation_present_required", "must be provided")
+	// ErrNotNullRequired is the error that returns when a value is null.
+	ErrNotNullRequired = NewError("validation_not_null_required", "cannot be null")
+	// ErrAbsentRequired is the error that returns when a value is provided.
+	ErrAbsentRequired = NewError("validation_absent_required", "must not be provided")
+)
+
+// Present is a validation rule that checks if a value is provided, even if it is null or a zero value.
+// For an Optional or a Nullable, it checks if the value appears in the input. For other values,
+// it checks if the value is not a nil pointer or interface.
+var Present = presenceRule{condition: true, check: isPresent, defaultErr: ErrPresentRequired}
+
+// NotNull is a validation rule that checks if a value is not null. An omitted Optional or Nullable is considered
+// valid. Use Present to make sure it is provided. For other values, it checks if the value is not nil in the
+// same way as NotNil.
+var NotNull = presenceRule{condition: true, check: isNotNull, defaultErr: ErrNotNullRequired}
+
+// Absent is a validation rule that checks if a value is not provided.
+// For an Optional or a Nullable, it checks if the value does not appear in the input. For other values,
+// it checks if the value is a nil pointer or interface.
+var Absent = presenceRule{condition: true, check: isAbsent, defaultErr: ErrAbsentRequired}
+
+type presenceRule struct {
+	condition  bool
+	check      func(value interface{}) bool
+	defaultErr Error
+	err        Error
+}
+
+// Validate checks if the given value is valid or not.
+func (r presenceRule) Validate(value interface{}) error {
+	if !r.condition {
+		return nil
+	}
+	if r.check(value) {
+		return nil
+	}
+	if r.err != nil {
+		return r.err
+	}
+	return r.defaultErr
+}
+
+// When sets the condition that determines if the validation should be performed.
+func (r presenceRule) When(condition bool) presenceRule {
+	r.condition = condition
+	return r
+}
+
+// Error sets the error message for the rule.
+func (r presenceRule) Error(message string) presenceRule {
+	if r.err == nil {
+		r.err = r.defaultErr
+	}
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r presenceRule) ErrorObject(err Error) presenceRule {
+	r.err = err
+	return r
+}
+
+func isPresent(value interface{}) bool {
+	if p, ok := indirectPresence(value); ok {
+		present, _ := p.presence()
+		return present
+	}
+	return !isNilValue(value)
+}
+
+func isNotNull(value interface{}) bool {
+	if p, ok := indirectPresence(value); ok {
+		_, null := p.presence()
+		return !null
+	}
+	_, isNil := Indirect(value)
+	return !isNil
+}
+
+func isAbsent(value interface{}) bool {
+	if p, ok := indirectPresence(value); ok {
+		present, _ := p.presence()
+		return !present
+	}
+	return isNilValue(value)
+}
+
+// isNilValue checks if a value is nil or a nil pointer or interface.
+func isNilValue(value interface{}) bool {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresenceRules(t *testing.T) {
+	s := ""
+	var nilStr *string
+	absent := Nullable[string]{}
+	null := Nullable[string]{Present: true}
+	value := Nullable[string]{V: "", Present: true, Valid: true}
+
+	tests := []struct {
+		tag     string
+		value   interface{}
+		present bool
+		notNull bool
+		absent  bool
+	}{
+		{"t1", absent, false, true, true},
+		{"t2", null, true, false, false},
+		{"t3", value, true, true, false},
+		{"t4", &null, true, false, false},
+		{"t5", (*Nullable[string])(nil), false, false, true},
+		{"t6", Optional[int]{}, false, true, true},
+		{"t7", Optional[int]{Present: true}, true, true, false},
+		{"t8", nil, false, false, true},
+		{"t9", nilStr, false, false, true},
+		{"t10", &s, true, true, false},
+		{"t11", 0, true, true, false},
+		{"t12", sql.NullString{}, true, false, false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.present, Present.Validate(test.value) == nil, test.tag)
+		assert.Equal(t, test.notNull, NotNull.Validate(test.value) == nil, test.tag)
+		assert.Equal(t, test.absent, Absent.Validate(test.value) == nil, test.tag)
+	}
+
+	assert.Equal(t, ErrPresentRequired, Present.Validate(absent))
+	assert.Equal(t, ErrNotNullRequired, NotNull.Validate(null))
+	assert.Equal(t, ErrAbsentRequired, Absent.Validate(value))
+}
+
+func TestPresenceRule_Options(t *testing.T) {
+	assert.Nil(t, Present.When(false).Validate(nil))
+	assert.EqualError(t, Present.When(true).Validate(nil), "must be provided")
+	assert.EqualError(t, Absent.Error("must be omitted").Validate(1), "must be omitted")
+	assert.Equal(t, "validation_absent_required", Absent.Error("x").Validate(1).(Error).Code())
+	err := NotNull.ErrorObject(NewError("code", "abc")).Validate(nil)
+	assert.Equal(t, "code", err.(Error).Code())
+}
@@ -17,6 +17,7 @@ var (
 // - bool: true
 // - string, array, slice, map: len() > 0
 // - interface, pointer: not nil and the referenced value is not empty
+// - types with custom emptiness (see IsEmpty): the registered function, IsEmpty() or IsZero() returns false
 // - any other types
 var Required = RequiredRule{skipNil: false, condition: true}
 
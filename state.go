@@ -0,0 +1,193 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// MaxDepth is the maximum nesting depth that a single validation call may reach while descending into
+// nested values, elements and rules. When the depth is exceeded, an ErrMaxDepthExceeded internal error is
+// returned. A value of 0 or less disables the limit. Use WithMaxDepth to set the limit for a single
+// context-aware validation call.
+var MaxDepth = 10000
+
+// ErrMaxDepthExceeded is the error that the nesting depth of a validation exceeds the configured maximum depth.
+type ErrMaxDepthExceeded struct {
+	// MaxDepth is the maximum depth that was exceeded.
+	MaxDepth int
+	// Path is the dot-separated path of the value being validated when the depth was exceeded.
+	Path string
+}
+
+// Error returns the error string of ErrMaxDepthExceeded.
+func (e ErrMaxDepthExceeded) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("maximum validation depth of %v exceeded", e.MaxDepth)
+	}
+	return fmt.Sprintf("maximum validation depth of %v exceeded at %v", e.MaxDepth, e.Path)
+}
+
+type (
+	// validationState is the state of a validation at a nesting level. It is immutable: a nested level derives
+	// a new state from the state of the enclosing level, so a state may be shared by concurrent validations.
+	validationState struct {
+		maxDepth int
+		depth    int
+		// entered is the pointer, map or slice validated at the current level, if any, and parents are those
+		// validated at the enclosing levels. The key of the current level is held inline, so that the list is
+		// allocated only when the values are nested in the same validation.
+		entered visitKey
+		parents *enteredValue
+		// hooked is the type of the value whose hooks are called around the validation at the current level.
+		hooked reflect.Type
+	}
+
+	// enteredValue is an element of the list of the values being validated on the path to a nesting level.
+	enteredValue struct {
+		key    visitKey
+		parent *enteredValue
+	}
+
+	// stateContext is the context carrying the validation state. It is passed to the validation methods and
+	// the context-aware rules, so that the nested validations they start continue from the state.
+	stateContext struct {
+		context.Context
+		state validationState
+	}
+
+	stateKey    struct{}
+	maxDepthKey struct{}
+)
+
+// nestingRule is implemented by the built-in rules that validate nested values, such as Each and Map.
+// These rules are given the validation state of the value they validate even if the validation is not
+// context-aware, so that depth limits and cycle detection apply through them.
+type nestingRule interface {
+	Rule
+	// validateWithState validates a value at the nesting level of the given state.
+	// The context is nil if the validation is not context-aware.
+	validateWithState(ctx context.Context, st validationState, value interface{}) error
+}
+
+// WithMaxDepth returns a copy of the context that sets the maximum nesting depth for the validation
+// calls using the context. It overrides MaxDepth. A value of 0 or less disables the limit.
+func WithMaxDepth(ctx context.Context, depth int) context.Context {
+	return context.WithValue(ctx, maxDepthKey{}, depth)
+}
+
+// Value returns the validation state for stateKey and delegates other keys to the parent context.
+func (c *stateContext) Value(key interface{}) interface{} {
+	if key == (stateKey{}) {
+		return &c.state
+	}
+	return c.Context.Value(key)
+}
+
+// stateOf returns the validation state carried by the given context. If the context carries no state,
+// the state of a new validation is returned. A nil context starts a validation that is not context-aware.
+func stateOf(ctx context.Context) validationState {
+	if ctx == nil {
+		return validationState{maxDepth: MaxDepth}
+	}
+	if c, ok := ctx.(*stateContext); ok {
+		return c.state
+	}
+	if st, ok := ctx.Value(stateKey{}).(*validationState); ok {
+		return *st
+	}
+	st := validationState{maxDepth: MaxDepth}
+	if depth, ok := ctx.Value(maxDepthKey{}).(int); ok {
+		st.maxDepth = depth
+	}
+	return st
+}
+
+// withState returns a context carrying the given validation state, derived from the context given by the caller
+// of the validation. It returns nil if the validation is not context-aware.
+func withState(ctx context.Context, st validationState) context.Context {
+	if ctx == nil {
+		return nil
+	}
+	if c, ok := ctx.(*stateContext); ok && c.state == st {
+		return c
+	}
+	return &stateContext{Context: userContext(ctx), state: st}
+}
+
+// userContext returns the context given by the caller of a validation, without the validation state.
+// It returns context.Background() if the validation is not context-aware.
+func userContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	if c, ok := ctx.(*stateContext); ok {
+		return c.Context
+	}
+	return ctx
+}
+
+// enter returns the state of the next nesting level, or an internal error if the maximum depth is exceeded.
+func (st validationState) enter() (validationState, error) {
+	if st.maxDepth > 0 && st.depth >= st.maxDepth {
+		return st, NewInternalError(ErrMaxDepthExceeded{MaxDepth: st.maxDepth})
+	}
+	st.depth++
+	st.hooked = nil
+	return st, nil
+}
+
+// visit returns the state marking a value as being validated. It returns false if the value is already
+// being validated on the current path, which means the data is cyclic. Such a value is considered valid,
+// as it is validated and its errors are reported where it is first encountered.
+func (st validationState) visit(key visitKey) (validationState, bool) {
+	if st.entered == key {
+		return st, false
+	}
+	for e := st.parents; e != nil; e = e.parent {
+		if e.key == key {
+			return st, false
+		}
+	}
+	if st.entered.typ != nil {
+		st.parents = &enteredValue{key: st.entered, parent: st.parents}
+	}
+	st.entered = key
+	return st, true
+}
+
+// prependPath prepends the given path element to the path of an ErrMaxDepthExceeded internal error and returns
+// other errors as is. The path is built while the error is returned through the enclosing values, so that
+// it is not tracked unless the depth is exceeded.
+func prependPath(err error, name string) error {
+	ie, ok := err.(InternalError)
+	if !ok {
+		return err
+	}
+	e, ok := ie.InternalError().(ErrMaxDepthExceeded)
+	if !ok {
+		return err
+	}
+	if e.Path == "" {
+		e.Path = name
+	} else {
+		e.Path = name + "." + e.Path
+	}
+	return NewInternalError(e)
+}
+
+// pointerKey returns the key identifying a pointer, map or slice value that may lead to cyclic data,
+// and a boolean indicating whether the value needs to be tracked.
+func pointerKey(v reflect.Value) (visitKey, bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		return visitKey{v.Pointer(), v.Type().Elem()}, !v.IsNil()
+	case reflect.Map, reflect.Slice:
+		return visitKey{v.Pointer(), v.Type()}, !v.IsNil() && v.Len() > 0
+	}
+	return visitKey{}, false
+}
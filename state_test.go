@@ -0,0 +1,198 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type chainNode struct {
+	Name string
+	Next *chainNode
+}
+
+func (n *chainNode) ValidateWithContext(ctx context.Context) error {
+	return ValidateStructWithContext(ctx, n,
+		Field(&n.Name, Required),
+		Field(&n.Next),
+	)
+}
+
+type plainNode struct {
+	Name string
+	Next *plainNode
+}
+
+func (n *plainNode) Validate() error {
+	return ValidateStruct(n,
+		Field(&n.Name, Required),
+		Field(&n.Next),
+	)
+}
+
+func newChain(length int) (*chainNode, *chainNode) {
+	head := &chainNode{Name: "node"}
+	tail := head
+	for i := 1; i < length; i++ {
+		tail.Next = &chainNode{Name: "node"}
+		tail = tail.Next
+	}
+	return head, tail
+}
+
+func TestValidate_MaxDepth(t *testing.T) {
+	head, tail := newChain(50)
+	tail.Name = ""
+	err := ValidateWithContext(context.Background(), head)
+	assert.EqualError(t, err, "Next: ("+strings.Repeat("Next: (", 48)+"Name: cannot be blank."+strings.Repeat(").", 49))
+
+	err = ValidateWithContext(WithMaxDepth(context.Background(), 20), head)
+	if assert.NotNil(t, err) {
+		ie, ok := err.(InternalError)
+		if assert.True(t, ok) {
+			e, ok := ie.InternalError().(ErrMaxDepthExceeded)
+			if assert.True(t, ok) {
+				assert.Equal(t, 20, e.MaxDepth)
+				assert.True(t, strings.HasPrefix(e.Path, "Next.Next."))
+			}
+		}
+	}
+
+	// the limit is disabled with a non-positive value
+	head, _ = newChain(500)
+	assert.Nil(t, ValidateWithContext(WithMaxDepth(context.Background(), 0), head))
+
+	// context-less validation uses MaxDepth
+	defer func(depth int) { MaxDepth = depth }(MaxDepth)
+	MaxDepth = 3
+	value := [][][]string{{{"a"}}}
+	err = Validate(value, Each(Each(Each(Required))))
+	assert.EqualError(t, err, "maximum validation depth of 3 exceeded at 0.0.0")
+	err = Validate(value, Each(Each(Required)))
+	assert.Nil(t, err)
+	err = Validate(map[string][][]string{"a": {{"b"}}}, Map(Key("a", Each(Each(Required)))))
+	assert.EqualError(t, err, "maximum validation depth of 3 exceeded at a.0.0")
+}
+
+func TestValidate_Cycle(t *testing.T) {
+	head, tail := newChain(5)
+	tail.Next = head
+	assert.Nil(t, ValidateWithContext(context.Background(), head))
+
+	head.Name = ""
+	err := ValidateWithContext(context.Background(), tail)
+	assert.EqualError(t, err, "Next: (Name: cannot be blank.).")
+
+	nodes := []*chainNode{head, head}
+	err = ValidateWithContext(context.Background(), nodes)
+	assert.EqualError(t, err, "0: (Name: cannot be blank.); 1: (Name: cannot be blank.).")
+}
+
+func TestValidate_WithoutContext(t *testing.T) {
+	// the Validate methods start new validations, so long chains are validated in linear time
+	head := &plainNode{Name: "a"}
+	tail := head
+	for i := 0; i < 3000; i++ {
+		tail.Next = &plainNode{Name: "a"}
+		tail = tail.Next
+	}
+	assert.Nil(t, Validate(head))
+
+	tail.Name = ""
+	err := head.Validate()
+	assert.True(t, strings.HasPrefix(err.Error(), "Next: (Next: (Next: ("))
+	assert.True(t, strings.HasSuffix(err.Error(), "Name: cannot be blank."+strings.Repeat(").", 3000)))
+}
+
+type concurrentNodes []*chainNode
+
+func (ns concurrentNodes) ValidateWithContext(ctx context.Context) error {
+	errs := make([]error, len(ns))
+	var wg sync.WaitGroup
+	for i, n := range ns {
+		wg.Add(1)
+		go func(i int, n *chainNode) {
+			defer wg.Done()
+			errs[i] = ValidateWithContext(ctx, n)
+		}(i, n)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestValidate_ConcurrentWithContext(t *testing.T) {
+	// the state carried by the context may be used by concurrent validations
+	head, _ := newChain(3)
+	nodes := concurrentNodes{head, head, head, head}
+	for i := 0; i < 10; i++ {
+		nodes = append(nodes, &chainNode{Name: "node", Next: head})
+	}
+	assert.Nil(t, ValidateWithContext(context.Background(), nodes))
+
+	ctx := WithMaxDepth(context.Background(), 4)
+	err := ValidateWithContext(ctx, nodes)
+	if assert.NotNil(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+	}
+}
+
+func TestErrMaxDepthExceeded_Error(t *testing.T) {
+	assert.Equal(t, "maximum validation depth of 5 exceeded", ErrMaxDepthExceeded{MaxDepth: 5}.Error())
+	assert.Equal(t, "maximum validation depth of 5 exceeded at a.0", ErrMaxDepthExceeded{MaxDepth: 5, Path: "a.0"}.Error())
+}
+
+func TestStateOf(t *testing.T) {
+	st := stateOf(nil)
+	assert.Equal(t, MaxDepth, st.maxDepth)
+	assert.Zero(t, st.depth)
+	assert.Nil(t, withState(nil, st))
+	assert.Equal(t, context.Background(), userContext(nil))
+
+	ctx := WithMaxDepth(context.WithValue(context.Background(), contains, "abc"), 7)
+	st = stateOf(ctx)
+	assert.Equal(t, 7, st.maxDepth)
+
+	st, err := st.enter()
+	assert.Nil(t, err)
+	sctx := withState(ctx, st)
+	assert.Equal(t, st, stateOf(sctx))
+	assert.Equal(t, "abc", sctx.Value(contains))
+	assert.Equal(t, ctx, userContext(sctx))
+	assert.Equal(t, sctx, withState(sctx, st))
+
+	// a context derived from the one carrying the state carries the state as well
+	assert.Equal(t, st, stateOf(context.WithValue(sctx, contains, "xyz")))
+
+	// the state of the enclosing level is not changed by the nested levels
+	st2, _ := st.enter()
+	assert.Equal(t, 1, st.depth)
+	assert.Equal(t, 2, st2.depth)
+	key := visitKey{1, reflect.TypeOf(0)}
+	st3, ok := st2.visit(key)
+	assert.True(t, ok)
+	_, ok = st3.visit(key)
+	assert.False(t, ok)
+	_, ok = st2.visit(key)
+	assert.True(t, ok)
+}
+
+func TestPrependPath(t *testing.T) {
+	err := prependPath(NewInternalError(ErrMaxDepthExceeded{MaxDepth: 3}), "b")
+	err = prependPath(err, "a")
+	assert.EqualError(t, err, "maximum validation depth of 3 exceeded at a.b")
+	assert.Equal(t, ErrRequired, prependPath(ErrRequired, "a"))
+}
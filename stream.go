@@ -0,0 +1,223 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+type (
+	// JSONStreamValidator validates the records of a JSON array or of newline-delimited JSON (NDJSON) read from
+	// an io.Reader one at a time, so that large inputs can be validated without decoding them as a whole.
+	// See JSONStream().
+	JSONStreamValidator[T any] struct {
+		rules     []Rule
+		maxErrors int
+		onError   func(ctx context.Context, err RecordError) error
+	}
+
+	// RecordError represents the error of an invalid record in a stream.
+	RecordError struct {
+		// Index is the zero-based index of the record in the stream.
+		Index int
+		// Offset is the byte offset of the record in the stream.
+		Offset int64
+		// Err is the validation error of the record, or the error of decoding the record into the record type.
+		Err error
+	}
+
+	// StreamErrors represents the result of validating a stream that has invalid records.
+	StreamErrors struct {
+		// Errors are the errors of the invalid records. They are not collected if the errors are passed
+		// to a callback specified by OnError() or to a channel specified by SendErrors().
+		Errors []RecordError
+		// Invalid is the number of the invalid records.
+		Invalid int
+		// Records is the number of the records that are read.
+		Records int
+		// Stopped indicates that the validation is stopped because the error budget is exhausted.
+		Stopped bool
+	}
+)
+
+// JSONStream returns a validator that validates each record of a JSON array or of newline-delimited JSON with
+// the given rules. Each record is decoded into a value of type T, such as a struct or map[string]interface{} for
+// the Map rule, and validated in the same way as Validate(), so a type implementing Validatable is validated by its
+// validation method. For example,
+//
+//	err := validation.JSONStream[Order]().MaxErrors(100).Validate(file)
+//
+// Only one record is held in memory at a time. The errors of the invalid records are collected and returned
+// as StreamErrors, unless they are passed to a callback with OnError() or to a channel with SendErrors().
+func JSONStream[T any](rules ...Rule) JSONStreamValidator[T] {
+	return JSONStreamValidator[T]{rules: rules}
+}
+
+// MaxErrors sets the error budget of the validation, which stops reading the stream after the given number of
+// invalid records. It also limits the number of the errors that are collected. If n is 0, the whole stream is read.
+func (v JSONStreamValidator[T]) MaxErrors(n int) JSONStreamValidator[T] {
+	v.maxErrors = n
+	return v
+}
+
+// OnError sets the callback that is called with the error of each invalid record as soon as it is found.
+// The errors passed to the callback are not collected. If the callback returns an error,
+// the validation is stopped and the error is returned.
+func (v JSONStreamValidator[T]) OnError(f func(ctx context.Context, err RecordError) error) JSONStreamValidator[T] {
+	v.onError = f
+	return v
+}
+
+// SendErrors sets the channel to send the error of each invalid record to as soon as it is found.
+// The errors sent to the channel are not collected. The channel is not closed by the validator.
+// If the context is canceled while waiting to send an error, the validation is stopped.
+func (v JSONStreamValidator[T]) SendErrors(ch chan<- RecordError) JSONStreamValidator[T] {
+	return v.OnError(func(ctx context.Context, err RecordError) error {
+		select {
+		case ch <- err:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// Validate reads and validates the records from the given reader.
+// It returns StreamErrors if any record is invalid, or the error of reading the stream, such as a syntax error.
+func (v JSONStreamValidator[T]) Validate(r io.Reader) error {
+	return v.ValidateWithContext(nil, r)
+}
+
+// ValidateWithContext reads and validates the records from the given reader with the given context.
+// The context-aware rules and validation methods are used, and the validation is stopped
+// with the error of the context if the context is canceled.
+// It returns StreamErrors if any record is invalid, or the error of reading the stream, such as a syntax error.
+func (v JSONStreamValidator[T]) ValidateWithContext(ctx context.Context, r io.Reader) error {
+	cbCtx := ctx
+	if cbCtx == nil {
+		cbCtx = context.Background()
+	}
+
+	br := bufio.NewReader(r)
+	skipped, err := skipSpace(br)
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(br)
+	isArray := false
+	if b, _ := br.Peek(1); len(b) == 1 && b[0] == '[' {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+		isArray = true
+	}
+
+	result := StreamErrors{}
+	for ; ; result.Records++ {
+		if ctx != nil {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if isArray && !dec.More() {
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+			break
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF && !isArray {
+			break
+		} else if err != nil {
+			return fmt.Errorf("record %v: %w", result.Records, err)
+		}
+
+		re := RecordError{Index: result.Records, Offset: skipped + dec.InputOffset() - int64(len(raw))}
+		if re.Err = v.validateRecord(ctx, raw); re.Err == nil {
+			continue
+		} else if ie, ok := re.Err.(InternalError); ok && ie.InternalError() != nil {
+			return re.Err
+		}
+
+		result.Invalid++
+		if v.onError != nil {
+			if err := v.onError(cbCtx, re); err != nil {
+				return err
+			}
+		} else {
+			result.Errors = append(result.Errors, re)
+		}
+		if v.maxErrors > 0 && result.Invalid >= v.maxErrors {
+			result.Records++
+			result.Stopped = true
+			break
+		}
+	}
+
+	if result.Invalid > 0 {
+		return result
+	}
+	return nil
+}
+
+// validateRecord decodes a record and validates it.
+func (v JSONStreamValidator[T]) validateRecord(ctx context.Context, raw json.RawMessage) error {
+	var record T
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return err
+	}
+	return validate(ctx, stateOf(ctx), record, reflect.ValueOf(&record), v.rules)
+}
+
+// skipSpace skips the leading white space of a JSON stream and returns the number of skipped bytes.
+func skipSpace(r *bufio.Reader) (int64, error) {
+	var n int64
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return n, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return n, r.UnreadByte()
+		}
+		n++
+	}
+}
+
+// Error returns the error string of RecordError.
+func (e RecordError) Error() string {
+	return fmt.Sprintf("record %v (offset %v): %v", e.Index, e.Offset, e.Err)
+}
+
+// Unwrap returns the error of the record.
+func (e RecordError) Unwrap() error {
+	return e.Err
+}
+
+// Error returns the error string of StreamErrors.
+func (e StreamErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v of %v records are invalid", e.Invalid, e.Records)
+	if e.Stopped {
+		b.WriteString(" (stopped after too many errors)")
+	}
+	for i, re := range e.Errors {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(re.Error())
+	}
+	return b.String()
+}
@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type streamRecord struct {
+	Name string `json:"name"`
+	Qty  int    `json:"qty"`
+}
+
+func (r *streamRecord) Validate() error {
+	return ValidateStruct(r,
+		Field(&r.Name, Required),
+		Field(&r.Qty, Min(0)),
+	)
+}
+
+func TestJSONStream(t *testing.T) {
+	tests := []struct {
+		tag   string
+		input string
+		err   string
+	}{
+		{"t1", ``, ""},
+		{"t2", ` [] `, ""},
+		{"t3", `[{"name":"a"},{"name":"b","qty":1}]`, ""},
+		{"t4", "[\n {\"name\":\"a\"},\n {\"name\":\"\"}\n]", "1 of 2 records are invalid: record 1 (offset 18): name: cannot be blank."},
+		{"t5", "{\"name\":\"a\"}\n{\"qty\":-1}\n\n{\"name\":\"c\"}\n", "1 of 3 records are invalid: record 1 (offset 13): name: cannot be blank; qty: must be no less than 0."},
+		{"t6", `[{"name":"a","qty":"x"}]`, "1 of 1 records are invalid: record 0 (offset 1): json: cannot unmarshal string into Go struct field streamRecord.qty of type int"},
+		{"t7", `[{"name":"a"},{"name":`, "record 1: unexpected EOF"},
+		{"t8", `[{"name":"a"} {"name":"b"}]`, "record 1: invalid character '{' after array element"},
+	}
+	for _, test := range tests {
+		err := JSONStream[streamRecord]().Validate(strings.NewReader(test.input))
+		assertError(t, test.err, err, test.tag)
+	}
+
+	// maps are validated with the given rules
+	err := JSONStream[map[string]interface{}](Map(Key("id", Required))).Validate(strings.NewReader(`{"id":1} {"id":0}`))
+	assertError(t, "1 of 2 records are invalid: record 1 (offset 9): id: cannot be blank.", err, "t9")
+	if assert.IsType(t, StreamErrors{}, err) {
+		se := err.(StreamErrors)
+		assert.Equal(t, 2, se.Records)
+		assert.Equal(t, 1, se.Invalid)
+		assert.False(t, se.Stopped)
+		var re RecordError
+		assert.True(t, errors.As(err.(StreamErrors).Errors[0], &re))
+		assert.Equal(t, int64(9), re.Offset)
+	}
+
+	// internal errors stop the validation
+	err = JSONStream[int](By(func(interface{}) error {
+		return NewInternalError(errors.New("failed"))
+	})).Validate(strings.NewReader(`[1, 2]`))
+	assertError(t, "failed", err, "t10")
+}
+
+func TestJSONStream_MaxErrors(t *testing.T) {
+	input := `[{"name":""},{"name":""},{"name":""},{"name":"d"}]`
+	err := JSONStream[streamRecord]().MaxErrors(2).Validate(strings.NewReader(input))
+	if assert.IsType(t, StreamErrors{}, err) {
+		se := err.(StreamErrors)
+		assert.Equal(t, 2, se.Records)
+		assert.Equal(t, 2, se.Invalid)
+		assert.True(t, se.Stopped)
+		assert.Len(t, se.Errors, 2)
+		assert.Equal(t, "2 of 2 records are invalid (stopped after too many errors): record 0 (offset 1): name: cannot be blank.; record 1 (offset 13): name: cannot be blank.", err.Error())
+	}
+}
+
+func TestJSONStream_OnError(t *testing.T) {
+	input := "{\"name\":\"\"}\n{\"name\":\"b\"}\n{\"name\":\"\"}\n"
+	var indexes []int
+	err := JSONStream[streamRecord]().OnError(func(ctx context.Context, err RecordError) error {
+		indexes = append(indexes, err.Index)
+		return nil
+	}).Validate(strings.NewReader(input))
+	assertError(t, "2 of 3 records are invalid", err, "t1")
+	assert.Equal(t, []int{0, 2}, indexes)
+
+	err = JSONStream[streamRecord]().OnError(func(ctx context.Context, err RecordError) error {
+		return errors.New("stop")
+	}).Validate(strings.NewReader(input))
+	assertError(t, "stop", err, "t2")
+
+	ch := make(chan RecordError, 10)
+	err = JSONStream[streamRecord]().SendErrors(ch).ValidateWithContext(context.Background(), strings.NewReader(input))
+	assertError(t, "2 of 3 records are invalid", err, "t3")
+	close(ch)
+	var offsets []int64
+	for re := range ch {
+		offsets = append(offsets, re.Offset)
+	}
+	assert.Equal(t, []int64{0, 25}, offsets)
+
+	// the validation is stopped when the context is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = JSONStream[streamRecord]().SendErrors(make(chan RecordError)).ValidateWithContext(ctx, strings.NewReader(input))
+	assert.Equal(t, context.Canceled, err)
+}
@@ -24,13 +24,34 @@ type (
 	// ErrFieldNotFound is the error that a field cannot be found in the struct.
 	ErrFieldNotFound int
 
-	// FieldRules represents a rule set associated with a struct field.
+	// ErrNilEmbeddedPointer is the error that a field cannot be found in the struct
+	// because an embedded struct pointer that may contain it is nil.
+	ErrNilEmbeddedPointer struct {
+		// Index is the position of the field in the list of fields being validated.
+		Index int
+		// Name is the name of the nil embedded pointer field.
+		Name string
+	}
+
+	// FieldRules represents a rule set associated with a struct field, or a rule validating the whole struct.
 	FieldRules struct {
 		fieldPtr interface{}
 		rules    []Rule
+		// err is the error found in the rules when the rule set is built.
+		err error
+		// structRule validates the whole struct, given as an addressable struct value, at the nesting level
+		// of the given state. It is specified via StructRule() or DiscriminatedFields().
+		structRule func(ctx context.Context, st validationState, value reflect.Value) error
 	}
+
+	// StructRuleFunc represents a function that validates a whole struct, given as a pointer to it.
+	StructRuleFunc func(ctx context.Context, structPtr interface{}) error
 )
 
+// StructErrorKey is the key of the validation errors returned by the functions specified via StructRule()
+// that are not keyed by field names. Return Errors from the function to report an error under another key.
+const StructErrorKey = "_root"
+
 // Error returns the error string of ErrFieldPointer.
 func (e ErrFieldPointer) Error() string {
 	return fmt.Sprintf("field #%v must be specified as a pointer", int(e))
@@ -41,6 +62,11 @@ func (e ErrFieldNotFound) Error() string {
 	return fmt.Sprintf("field #%v cannot be found in the struct", int(e))
 }
 
+// Error returns the error string of ErrNilEmbeddedPointer.
+func (e ErrNilEmbeddedPointer) Error() string {
+	return fmt.Sprintf("field #%v cannot be found in the struct: embedded pointer %v is nil", e.Index, e.Name)
+}
+
 // ValidateStruct validates a struct by checking the specified struct fields against the corresponding validation rules.
 // Note that the struct being validated must be specified as a pointer to it. If the pointer is nil, it is considered valid.
 // Use Field() to specify struct fields that need to be validated. Each Field() call specifies a single field which
@@ -58,6 +84,10 @@ func (e ErrFieldNotFound) Error() string {
 //	fmt.Println(err)
 //	// Value: the length must be between 5 and 10.
 //
+// If the struct implements BeforeValidator or AfterValidator but not Validatable, the hooks are called with
+// context.Background() before and after the fields are validated. The hooks of a Validatable struct are called
+// by Validate around its Validate method instead.
+//
 // An error will be returned if validation fails.
 func ValidateStruct(structPtr interface{}, fields ...*FieldRules) error {
 	return ValidateStructWithContext(nil, structPtr, fields...)
@@ -66,8 +96,50 @@ func ValidateStruct(structPtr interface{}, fields ...*FieldRules) error {
 // ValidateStructWithContext validates a struct with the given context.
 // The only difference between ValidateStructWithContext and ValidateStruct is that the former will
 // validate struct fields with the provided context.
+// If the struct implements BeforeValidator or AfterValidator, the hooks are called before and after
+// the fields are validated, unless they are already called by the ValidateWithContext call that
+// dispatches to the struct's ValidateWithContext method.
 // Please refer to ValidateStruct for the detailed instructions on how to use this function.
 func ValidateStructWithContext(ctx context.Context, structPtr interface{}, fields ...*FieldRules) error {
+	return validateStruct(ctx, structPtr, false, fields)
+}
+
+// ValidateStructDeep validates a struct in the same way as ValidateStruct. In addition, it walks every exported
+// field that is not specified via Field() and validates the nested values implementing Validatable, including
+// elements of slices, arrays and maps, and values that implement Validatable with a pointer receiver.
+// Struct fields that do not implement Validatable themselves are walked recursively.
+// The errors of the nested values are reported under the corresponding field names, as with ValidateStruct.
+// Pointers that have already been visited are skipped, so self-referencing data does not cause infinite recursion.
+// For example,
+//
+//	type Order struct {
+//	    ID              string
+//	    ShippingAddress Address  // Address implements Validatable
+//	    Items           []Item   // Item implements Validatable
+//	}
+//
+//	err := validation.ValidateStructDeep(&order,
+//	    validation.Field(&order.ID, validation.Required),
+//	)
+//
+// The above code validates ID with the Required rule as well as the shipping address and each item.
+func ValidateStructDeep(structPtr interface{}, fields ...*FieldRules) error {
+	return validateStruct(nil, structPtr, true, fields)
+}
+
+// ValidateStructDeepWithContext validates a struct deeply with the given context.
+// The only difference between ValidateStructDeepWithContext and ValidateStructDeep is that the former will
+// validate struct fields with the provided context, and nested values implementing ValidatableWithContext will be
+// called with a context that carries the visited pointers. Nested types that call ValidateStructDeepWithContext
+// from their ValidateWithContext method thus share the cycle detection with the outer call.
+// Please refer to ValidateStructDeep for the detailed instructions on how to use this function.
+func ValidateStructDeepWithContext(ctx context.Context, structPtr interface{}, fields ...*FieldRules) error {
+	return validateStruct(ctx, structPtr, true, fields)
+}
+
+// validateStruct validates a struct with the given rules. If deep is true, the exported fields
+// that are not specified in the rules are validated deeply.
+func validateStruct(ctx context.Context, structPtr interface{}, deep bool, fields []*FieldRules) error {
 	value := reflect.ValueOf(structPtr)
 	if value.Kind() != reflect.Ptr || !value.IsNil() && value.Elem().Kind() != reflect.Struct {
 		// must be a pointer to a struct
@@ -77,42 +149,75 @@ func ValidateStructWithContext(ctx context.Context, structPtr interface{}, field
 		// treat a nil struct pointer as valid
 		return nil
 	}
-	value = value.Elem()
 
+	st := stateOf(ctx)
+	before, after := structHooks(ctx, st, value)
+	st, err := st.enter()
+	if err != nil {
+		return err
+	}
+
+	for _, fr := range fields {
+		if fr.err != nil {
+			return NewInternalError(fr.err)
+		}
+	}
+
+	if before == nil && after == nil {
+		return validateStructFields(ctx, st, value.Elem(), deep, fields)
+	}
+	return runHooks(userContext(ctx), before, after, func() error {
+		return validateStructFields(ctx, st, value.Elem(), deep, fields)
+	})
+}
+
+// validateStructFields validates the fields of a struct with the given rules at the nesting level of the given state.
+func validateStructFields(ctx context.Context, st validationState, value reflect.Value, deep bool, fields []*FieldRules) error {
 	errs := Errors{}
 
 	for i, fr := range fields {
+		if fr.structRule != nil {
+			if err := fr.structRule(ctx, st, value); err != nil {
+				if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+					return err
+				}
+				es, ok := err.(Errors)
+				if !ok {
+					es = Errors{StructErrorKey: err}
+				}
+				mergeErrors(errs, es)
+			}
+			continue
+		}
 		fv := reflect.ValueOf(fr.fieldPtr)
 		if fv.Kind() != reflect.Ptr {
 			return NewInternalError(ErrFieldPointer(i))
 		}
-		ft := findStructFieldCached(value, fv)
-		if ft == nil {
-			ft = findStructField(value, fv)
-		}
-		if ft == nil {
+		fm, nilEmbedded := findStructFieldCached(value, fv)
+		if fm == nil {
+			if nilEmbedded != nil {
+				return NewInternalError(ErrNilEmbeddedPointer{Index: i, Name: nilEmbedded.Name})
+			}
 			return NewInternalError(ErrFieldNotFound(i))
 		}
-		var err error
-		if ctx == nil {
-			err = Validate(fv.Elem().Interface(), fr.rules...)
-		} else {
-			err = ValidateWithContext(ctx, fv.Elem().Interface(), fr.rules...)
-		}
-		if err != nil {
+		if err := validate(ctx, st, fv.Elem().Interface(), fv, fr.rules); err != nil {
 			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
-				return err
+				return prependPath(err, fieldPath(fm))
 			}
-			if ft.Anonymous {
-				// merge errors from anonymous struct field
-				if es, ok := err.(Errors); ok {
-					for name, value := range es {
-						errs[name] = value
-					}
-					continue
-				}
+			addFieldError(errs, fm, err)
+		}
+	}
+
+	if deep {
+		// the struct is on the path of the deep validation, unless it is already being validated
+		st, _ = st.visit(visitKey{value.UnsafeAddr(), value.Type()})
+		d := newDeepValidator(ctx, fields)
+		if err := d.validateStruct(st, value); err != nil {
+			if es, ok := err.(Errors); ok {
+				mergeErrors(errs, es)
+			} else {
+				return err
 			}
-			errs[getErrorFieldName(ft)] = err
 		}
 	}
 
@@ -122,43 +227,109 @@ func ValidateStructWithContext(ctx context.Context, structPtr interface{}, field
 	return nil
 }
 
+// fieldPath returns the dot-separated error names of a struct field and the fields of the nested structs containing it.
+func fieldPath(fm *fieldMatch) string {
+	path := ""
+	for i := range fm.parents {
+		path += getErrorFieldName(&fm.parents[i]) + "."
+	}
+	return path + getErrorFieldName(&fm.field)
+}
+
+// mergeErrors merges the errors in src into dst. When both contain Errors for the same key, they are
+// merged recursively. Otherwise, the error already in dst takes precedence.
+func mergeErrors(dst, src Errors) {
+	for key, err := range src {
+		existing, found := dst[key]
+		if !found || existing == nil {
+			dst[key] = err
+			continue
+		}
+		des, ok1 := existing.(Errors)
+		ses, ok2 := err.(Errors)
+		if ok1 && ok2 {
+			mergeErrors(des, ses)
+		}
+	}
+}
+
 // Field specifies a struct field and the corresponding validation rules.
 // The struct field must be specified as a pointer to it.
+// Values transformed by transformation rules, such as Trim, are stored back into the field.
+// The types of the default values specified by Default() are checked against the field type.
 func Field(fieldPtr interface{}, rules ...Rule) *FieldRules {
+	// keep Field small enough to be inlined, so that the rule set need not be allocated on the heap
 	return &FieldRules{
 		fieldPtr: fieldPtr,
 		rules:    rules,
+		err:      checkFieldDefaults(fieldPtr, rules),
 	}
 }
 
-// findStructField looks for a field in the given struct.
-// The field being looked for should be a pointer to the actual struct field.
-// If found, the field info will be returned. Otherwise, nil will be returned.
-func findStructField(structValue reflect.Value, fieldValue reflect.Value) *reflect.StructField {
-	ptr := fieldValue.Pointer()
-	for i := structValue.NumField() - 1; i >= 0; i-- {
-		sf := structValue.Type().Field(i)
-		if ptr == structValue.Field(i).UnsafeAddr() {
-			// do additional type comparison because it's possible that the address of
-			// an embedded struct is the same as the first field of the embedded struct
-			if sf.Type == fieldValue.Elem().Type() {
-				return &sf
+// StructRule specifies a function that validates the whole struct, such as checks involving multiple fields.
+// It can be used with ValidateStruct() alongside Field() and is called in the order it is specified.
+// The function is given the pointer to the struct being validated. For example,
+//
+//	validation.ValidateStruct(&order,
+//	    validation.Field(&order.Allocations, validation.Required),
+//	    validation.StructRule(func(ctx context.Context, structPtr interface{}) error {
+//	        if order.TotalPercent() != 100 {
+//	            return validation.Errors{"allocations": errors.New("must add up to 100 percent")}
+//	        }
+//	        return nil
+//	    }),
+//	)
+//
+// If the function returns Errors, they are merged into the errors of the fields. An InternalError is returned
+// as is. Any other error is reported under the key StructErrorKey. The context given to the function is never nil,
+// even if the struct is validated by ValidateStruct().
+func StructRule(f StructRuleFunc) *FieldRules {
+	return &FieldRules{structRule: func(ctx context.Context, st validationState, value reflect.Value) error {
+		ctx = withState(ctx, st)
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		return f(ctx, value.Addr().Interface())
+	}}
+}
+
+// addFieldError adds the validation error of a struct field to errs. Errors of fields inside
+// nested structs are placed under the error names of the enclosing fields, and errors of an
+// anonymous struct field are merged into the level the field belongs to.
+func addFieldError(errs Errors, fm *fieldMatch, err error) {
+	for i := range fm.parents {
+		name := getErrorFieldName(&fm.parents[i])
+		nested, ok := errs[name].(Errors)
+		if !ok {
+			if errs[name] != nil {
+				// an error reported on the enclosing field itself takes precedence
+				return
 			}
+			nested = Errors{}
+			errs[name] = nested
 		}
-		if sf.Anonymous {
-			// delve into anonymous struct to look for the field
-			fi := structValue.Field(i)
-			if sf.Type.Kind() == reflect.Ptr {
-				fi = fi.Elem()
+		errs = nested
+	}
+	if fm.field.Anonymous {
+		// merge errors from anonymous struct field
+		if es, ok := err.(Errors); ok {
+			for name, value := range es {
+				errs[name] = value
 			}
-			if fi.Kind() == reflect.Struct {
-				if f := findStructField(fi, fieldValue); f != nil {
-					return f
-				}
+			return
+		}
+	}
+	name := getErrorFieldName(&fm.field)
+	if nested, ok := errs[name].(Errors); ok {
+		// the field already holds errors of its nested fields
+		if es, ok := err.(Errors); ok {
+			for key, value := range es {
+				nested[key] = value
 			}
+			return
 		}
 	}
-	return nil
+	errs[name] = err
 }
 
 // getErrorFieldName returns the name that should be used to represent the validation error of a struct field.
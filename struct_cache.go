@@ -5,63 +5,152 @@ import (
 	"sync"
 )
 
+// fieldCacheEntry is a field of a struct type. The index of its fieldMatch is the index sequence used to reach
+// the field from the cached struct type. The entry's fieldMatch is shared and must not be modified.
 type fieldCacheEntry struct {
-	field  reflect.StructField
+	fieldMatch
 	offset uintptr
 }
 
+// structCacheEntry holds the fields that can be located within a struct type.
+type structCacheEntry struct {
+	// fields are located at a fixed offset from the start of the struct.
+	fields []fieldCacheEntry
+	// pointers are struct pointer fields whose targets must be resolved at lookup time.
+	pointers []fieldCacheEntry
+}
+
+// fieldMatch describes a struct field located by a field pointer.
+type fieldMatch struct {
+	field reflect.StructField
+	// parents are the non-embedded struct fields enclosing the field, outermost first.
+	parents []reflect.StructField
+	// index is the index sequence used to reach the field from the struct, stepping through pointers.
+	index []int
+}
+
 var (
 	fieldCacheMu sync.RWMutex
-	fieldCache   = make(map[reflect.Type][]fieldCacheEntry)
+	fieldCache   = make(map[reflect.Type]*structCacheEntry)
 )
 
-func getFieldEntries(structType reflect.Type) []fieldCacheEntry {
+func getFieldEntries(structType reflect.Type) *structCacheEntry {
 	fieldCacheMu.RLock()
-	entries, ok := fieldCache[structType]
+	entry, ok := fieldCache[structType]
 	fieldCacheMu.RUnlock()
 	if ok {
-		return entries
+		return entry
 	}
 
 	fieldCacheMu.Lock()
 	defer fieldCacheMu.Unlock()
 
-	if entries, ok = fieldCache[structType]; ok {
-		return entries
+	if entry, ok = fieldCache[structType]; ok {
+		return entry
 	}
 
-	entries = buildFieldEntries(structType, 0)
-	fieldCache[structType] = entries
-	return entries
+	entry = &structCacheEntry{fields: buildFieldEntries(structType, 0, nil, nil)}
+	for _, e := range entry.fields {
+		if e.field.Type.Kind() == reflect.Ptr && e.field.Type.Elem().Kind() == reflect.Struct {
+			entry.pointers = append(entry.pointers, e)
+		}
+	}
+	fieldCache[structType] = entry
+	return entry
 }
 
-func buildFieldEntries(structType reflect.Type, baseOffset uintptr) []fieldCacheEntry {
+// buildFieldEntries flattens the fields of a struct type, descending into both embedded
+// and nested struct values. Fields reached through a pointer are not included since their
+// location is only known at runtime.
+func buildFieldEntries(structType reflect.Type, baseOffset uintptr, baseIndex []int, parents []reflect.StructField) []fieldCacheEntry {
 	var entries []fieldCacheEntry
 	for i := 0; i < structType.NumField(); i++ {
 		sf := structType.Field(i)
+		index := append(append([]int{}, baseIndex...), i)
 		entries = append(entries, fieldCacheEntry{
-			field:  sf,
-			offset: baseOffset + sf.Offset,
+			fieldMatch: fieldMatch{field: sf, parents: parents, index: index},
+			offset:     baseOffset + sf.Offset,
 		})
-		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
-			entries = append(entries, buildFieldEntries(sf.Type, baseOffset+sf.Offset)...)
+		if sf.Type.Kind() != reflect.Struct {
+			continue
+		}
+		if sf.Anonymous {
+			entries = append(entries, buildFieldEntries(sf.Type, baseOffset+sf.Offset, index, parents)...)
+		} else {
+			nested := append(append([]reflect.StructField{}, parents...), sf)
+			entries = append(entries, buildFieldEntries(sf.Type, baseOffset+sf.Offset, index, nested)...)
 		}
 	}
 	return entries
 }
 
-func findStructFieldCached(structValue reflect.Value, fieldValue reflect.Value) *reflect.StructField {
+// findStructFieldCached looks for a field in the given struct using the cached field layout.
+// Fields promoted from embedded struct pointers and fields of nested structs (by value or
+// by pointer) are supported. If the field cannot be found and a nil embedded pointer was
+// encountered during the lookup, that embedded field is returned as the second value.
+func findStructFieldCached(structValue reflect.Value, fieldValue reflect.Value) (*fieldMatch, *reflect.StructField) {
+	return findFieldInStruct(structValue, fieldValue, nil)
+}
+
+// visitKey identifies a value at a memory address that has been visited while traversing data.
+type visitKey struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+func findFieldInStruct(structValue reflect.Value, fieldValue reflect.Value, visited map[visitKey]bool) (*fieldMatch, *reflect.StructField) {
 	fieldPtr := fieldValue.Pointer()
 	structPtr := structValue.UnsafeAddr()
-	fieldOffset := fieldPtr - structPtr
+	entry := getFieldEntries(structValue.Type())
+
+	if fieldPtr >= structPtr && fieldPtr-structPtr < structValue.Type().Size() {
+		fieldOffset := fieldPtr - structPtr
+		for i := range entry.fields {
+			e := &entry.fields[i]
+			if e.offset == fieldOffset && e.field.Type == fieldValue.Type().Elem() {
+				return &e.fieldMatch, nil
+			}
+		}
+	}
+	if len(entry.pointers) == 0 {
+		return nil, nil
+	}
 
-	entries := getFieldEntries(structValue.Type())
+	if visited == nil {
+		visited = map[visitKey]bool{}
+	}
+	visited[visitKey{structPtr, structValue.Type()}] = true
 
-	for i := range entries {
-		if entries[i].offset == fieldOffset && entries[i].field.Type == fieldValue.Elem().Type() {
-			return &entries[i].field
+	var nilEmbedded *reflect.StructField
+	for i := range entry.pointers {
+		e := &entry.pointers[i]
+		pv := structValue.FieldByIndex(e.index)
+		if pv.IsNil() {
+			if e.field.Anonymous && nilEmbedded == nil {
+				nilEmbedded = &e.field
+			}
+			continue
+		}
+		target := pv.Elem()
+		if visited[visitKey{target.UnsafeAddr(), target.Type()}] {
+			continue
+		}
+		m, nilPtr := findFieldInStruct(target, fieldValue, visited)
+		if m != nil {
+			parents := append([]reflect.StructField{}, e.parents...)
+			if !e.field.Anonymous {
+				parents = append(parents, e.field)
+			}
+			return &fieldMatch{
+				field:   m.field,
+				parents: append(parents, m.parents...),
+				index:   append(append([]int{}, e.index...), m.index...),
+			}, nil
+		}
+		if nilEmbedded == nil {
+			nilEmbedded = nilPtr
 		}
 	}
 
-	return nil
+	return nil, nilEmbedded
 }
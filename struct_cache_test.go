@@ -14,11 +14,13 @@ import (
 
 func TestBuildFieldEntries(t *testing.T) {
 	type1 := reflect.TypeOf(Struct1{})
-	entries := buildFieldEntries(type1, 0)
+	entries := buildFieldEntries(type1, 0, nil, nil)
 
 	byName := make(map[string]fieldCacheEntry)
 	for _, e := range entries {
-		byName[e.field.Name] = e
+		if len(e.parents) == 0 {
+			byName[e.field.Name] = e
+		}
 	}
 
 	assert.Equal(t, type1.Field(0).Offset, byName["Field1"].offset)
@@ -30,13 +32,27 @@ func TestBuildFieldEntries(t *testing.T) {
 	assert.True(t, ok)
 	assert.Contains(t, byName, "Field21")
 	assert.Equal(t, struct2Field.Offset+field21.Offset, byName["Field21"].offset)
+	assert.Equal(t, []int{5, 0}, byName["Field21"].index)
 	assert.Contains(t, byName, "Field22")
 
+	// fields of a nested struct value are flattened with the enclosing field as parent
+	s2Field, ok := type1.FieldByName("S2")
+	assert.True(t, ok)
+	var nested []fieldCacheEntry
+	for _, e := range entries {
+		if len(e.parents) == 1 && e.parents[0].Name == "S2" {
+			nested = append(nested, e)
+		}
+	}
+	if assert.Len(t, nested, 2) {
+		assert.Equal(t, "Field21", nested[0].field.Name)
+		assert.Equal(t, s2Field.Offset+field21.Offset, nested[0].offset)
+	}
+
 	// fields promoted through a pointer-embedded anonymous struct are NOT
-	// flattened; ValidateStruct relies on the findStructField fallback for
-	// this case (see struct.go)
+	// flattened because their location is only known at runtime
 	type3 := reflect.TypeOf(Struct3{})
-	entries3 := buildFieldEntries(type3, 0)
+	entries3 := buildFieldEntries(type3, 0, nil, nil)
 	byName3 := make(map[string]fieldCacheEntry)
 	for _, e := range entries3 {
 		byName3[e.field.Name] = e
@@ -53,18 +69,21 @@ func TestGetFieldEntries(t *testing.T) {
 	delete(fieldCache, typ)
 	fieldCacheMu.Unlock()
 
-	entries := getFieldEntries(typ)
-	assert.NotEmpty(t, entries)
+	entry := getFieldEntries(typ)
+	assert.NotEmpty(t, entry.fields)
+	if assert.Len(t, entry.pointers, 1) {
+		assert.Equal(t, "S1", entry.pointers[0].field.Name)
+	}
 
 	fieldCacheMu.RLock()
 	cached, ok := fieldCache[typ]
 	fieldCacheMu.RUnlock()
 	assert.True(t, ok)
-	assert.Equal(t, entries, cached)
+	assert.Equal(t, entry, cached)
 
 	// a second call must hit the cache and return the same data
 	again := getFieldEntries(typ)
-	assert.Equal(t, entries, again)
+	assert.Equal(t, entry, again)
 }
 
 func TestGetFieldEntries_Concurrent(t *testing.T) {
@@ -79,7 +98,7 @@ func TestGetFieldEntries_Concurrent(t *testing.T) {
 
 	const goroutines = 50
 	var wg sync.WaitGroup
-	results := make([][]fieldCacheEntry, goroutines)
+	results := make([]*structCacheEntry, goroutines)
 	for i := 0; i < goroutines; i++ {
 		wg.Add(1)
 		go func(i int) {
@@ -95,29 +114,72 @@ func TestGetFieldEntries_Concurrent(t *testing.T) {
 }
 
 func TestFindStructFieldCached(t *testing.T) {
+	find := func(structValue, fieldValue reflect.Value) *fieldMatch {
+		m, _ := findStructFieldCached(structValue, fieldValue)
+		return m
+	}
+
 	var s1 Struct1
 	v1 := reflect.ValueOf(&s1).Elem()
-	assert.NotNil(t, findStructFieldCached(v1, reflect.ValueOf(&s1.Field1)))
-	assert.Nil(t, findStructFieldCached(v1, reflect.ValueOf(s1.Field2)))
-	assert.NotNil(t, findStructFieldCached(v1, reflect.ValueOf(&s1.Field2)))
-	assert.Nil(t, findStructFieldCached(v1, reflect.ValueOf(s1.Field3)))
-	assert.NotNil(t, findStructFieldCached(v1, reflect.ValueOf(&s1.Field3)))
-	assert.NotNil(t, findStructFieldCached(v1, reflect.ValueOf(&s1.Field4)))
-	assert.NotNil(t, findStructFieldCached(v1, reflect.ValueOf(&s1.field5)))
-	assert.NotNil(t, findStructFieldCached(v1, reflect.ValueOf(&s1.Struct2)))
-	assert.Nil(t, findStructFieldCached(v1, reflect.ValueOf(s1.S1)))
-	assert.NotNil(t, findStructFieldCached(v1, reflect.ValueOf(&s1.S1)))
-	assert.NotNil(t, findStructFieldCached(v1, reflect.ValueOf(&s1.Field21)))
-	assert.NotNil(t, findStructFieldCached(v1, reflect.ValueOf(&s1.Field22)))
+	assert.NotNil(t, find(v1, reflect.ValueOf(&s1.Field1)))
+	assert.Nil(t, find(v1, reflect.ValueOf(s1.Field2)))
+	assert.NotNil(t, find(v1, reflect.ValueOf(&s1.Field2)))
+	assert.Nil(t, find(v1, reflect.ValueOf(s1.Field3)))
+	assert.NotNil(t, find(v1, reflect.ValueOf(&s1.Field3)))
+	assert.NotNil(t, find(v1, reflect.ValueOf(&s1.Field4)))
+	assert.NotNil(t, find(v1, reflect.ValueOf(&s1.field5)))
+	assert.NotNil(t, find(v1, reflect.ValueOf(&s1.Struct2)))
+	assert.Nil(t, find(v1, reflect.ValueOf(s1.S1)))
+	assert.NotNil(t, find(v1, reflect.ValueOf(&s1.S1)))
+	assert.NotNil(t, find(v1, reflect.ValueOf(&s1.Field21)))
+	assert.NotNil(t, find(v1, reflect.ValueOf(&s1.Field22)))
 	s2 := reflect.ValueOf(&s1.Struct2).Elem()
-	assert.NotNil(t, findStructFieldCached(s2, reflect.ValueOf(&s1.Field21)))
-	assert.NotNil(t, findStructFieldCached(s2, reflect.ValueOf(&s1.Field22)))
+	assert.NotNil(t, find(s2, reflect.ValueOf(&s1.Field21)))
+	assert.NotNil(t, find(s2, reflect.ValueOf(&s1.Field22)))
 
-	// findStructFieldCached does not flatten fields promoted through a
-	// pointer-embedded anonymous struct; ValidateStruct falls back to
-	// findStructField to handle this case (see struct.go).
+	// fields promoted through a pointer-embedded anonymous struct
 	s3 := Struct3{Struct2: &Struct2{}}
 	v3 := reflect.ValueOf(&s3).Elem()
-	assert.NotNil(t, findStructFieldCached(v3, reflect.ValueOf(&s3.Struct2)))
-	assert.Nil(t, findStructFieldCached(v3, reflect.ValueOf(&s3.Field21)))
+	assert.NotNil(t, find(v3, reflect.ValueOf(&s3.Struct2)))
+	if m := find(v3, reflect.ValueOf(&s3.Field21)); assert.NotNil(t, m) {
+		assert.Equal(t, "Field21", m.field.Name)
+		assert.Empty(t, m.parents)
+	}
+
+	// fields of nested struct values and pointers
+	if m := find(v1, reflect.ValueOf(&s1.S2.Field22)); assert.NotNil(t, m) {
+		assert.Equal(t, "Field22", m.field.Name)
+		if assert.Len(t, m.parents, 1) {
+			assert.Equal(t, "S2", m.parents[0].Name)
+		}
+	}
+	s1.S1 = &Struct2{}
+	if m := find(v1, reflect.ValueOf(&s1.S1.Field21)); assert.NotNil(t, m) {
+		assert.Equal(t, "Field21", m.field.Name)
+		if assert.Len(t, m.parents, 1) {
+			assert.Equal(t, "S1", m.parents[0].Name)
+		}
+	}
+
+	// a nil embedded pointer is reported when the field cannot be found
+	other := Struct2{}
+	s4 := Struct3{}
+	m, nilEmbedded := findStructFieldCached(reflect.ValueOf(&s4).Elem(), reflect.ValueOf(&other.Field21))
+	assert.Nil(t, m)
+	if assert.NotNil(t, nilEmbedded) {
+		assert.Equal(t, "Struct2", nilEmbedded.Name)
+	}
+}
+
+func TestFindStructFieldCached_Cycle(t *testing.T) {
+	type node struct {
+		Value int
+		Next  *node
+	}
+	n := &node{}
+	n.Next = n
+	var other int
+	m, nilEmbedded := findStructFieldCached(reflect.ValueOf(n).Elem(), reflect.ValueOf(&other))
+	assert.Nil(t, m)
+	assert.Nil(t, nilEmbedded)
 }
@@ -35,34 +35,6 @@ type Struct3 struct {
 	S1 string
 }
 
-func TestFindStructField(t *testing.T) {
-	var s1 Struct1
-	v1 := reflect.ValueOf(&s1).Elem()
-	assert.NotNil(t, findStructField(v1, reflect.ValueOf(&s1.Field1)))
-	assert.Nil(t, findStructField(v1, reflect.ValueOf(s1.Field2)))
-	assert.NotNil(t, findStructField(v1, reflect.ValueOf(&s1.Field2)))
-	assert.Nil(t, findStructField(v1, reflect.ValueOf(s1.Field3)))
-	assert.NotNil(t, findStructField(v1, reflect.ValueOf(&s1.Field3)))
-	assert.NotNil(t, findStructField(v1, reflect.ValueOf(&s1.Field4)))
-	assert.NotNil(t, findStructField(v1, reflect.ValueOf(&s1.field5)))
-	assert.NotNil(t, findStructField(v1, reflect.ValueOf(&s1.Struct2)))
-	assert.Nil(t, findStructField(v1, reflect.ValueOf(s1.S1)))
-	assert.NotNil(t, findStructField(v1, reflect.ValueOf(&s1.S1)))
-	assert.NotNil(t, findStructField(v1, reflect.ValueOf(&s1.Field21)))
-	assert.NotNil(t, findStructField(v1, reflect.ValueOf(&s1.Field22)))
-	assert.NotNil(t, findStructField(v1, reflect.ValueOf(&s1.Field22)))
-	s2 := reflect.ValueOf(&s1.Struct2).Elem()
-	assert.NotNil(t, findStructField(s2, reflect.ValueOf(&s1.Field21)))
-	assert.NotNil(t, findStructField(s2, reflect.ValueOf(&s1.Field21)))
-	assert.NotNil(t, findStructField(s2, reflect.ValueOf(&s1.Field22)))
-	s3 := Struct3{
-		Struct2: &Struct2{},
-	}
-	v3 := reflect.ValueOf(&s3).Elem()
-	assert.NotNil(t, findStructField(v3, reflect.ValueOf(&s3.Struct2)))
-	assert.NotNil(t, findStructField(v3, reflect.ValueOf(&s3.Field21)))
-}
-
 func TestValidateStruct(t *testing.T) {
 	var m0 *Model1
 	m1 := Model1{A: "abc", B: "xyz", c: "abc", G: "xyz", H: []string{"abc", "abc"}, I: map[string]string{"foo": "abc"}}
@@ -120,6 +92,10 @@ func TestValidateStruct(t *testing.T) {
 		{"t8.6", &m4, []*FieldRules{Field(&m4.Model3)}, ""},
 		{"t8.7", &m3, []*FieldRules{Field(&m3.A, Required), Field(&m3.B, Required)}, "A: cannot be blank; B: cannot be blank."},
 		{"t8.8", &m3, []*FieldRules{Field(&m4.A, Required)}, "field #0 cannot be found in the struct"},
+		// nested struct fields
+		{"t8.9", &m3, []*FieldRules{Field(&m3.M3.A, Required), Field(&m3.B, Required)}, "B: cannot be blank; M3: (A: cannot be blank.)."},
+		{"t8.10", &m4, []*FieldRules{Field(&m4.M3.A, Length(5, 10))}, "M3: (A: the length must be between 5 and 10.)."},
+		{"t8.11", &m3, []*FieldRules{Field(&m3.M3.A, Required), Field(&m3.M3, Required)}, "M3: cannot be blank."},
 		// internal error
 		{"t9.1", &m5, []*FieldRules{Field(&m5.A, &validateAbc{}), Field(&m5.B, Required), Field(&m5.A, &validateInternalError{})}, "error internal"},
 	}
@@ -190,19 +166,78 @@ func TestValidateStructWithContext(t *testing.T) {
 	}
 }
 
+func TestValidateStruct_NestedFields(t *testing.T) {
+	type Geo struct {
+		Lat float64 `json:"lat"`
+	}
+	type Address struct {
+		Geo
+		Street string `json:"street"`
+		City   string `json:"city"`
+	}
+	type Customer struct {
+		Name     string   `json:"name"`
+		Address  Address  `json:"address"`
+		Shipping *Address `json:"shipping"`
+	}
+
+	c := Customer{Shipping: &Address{}}
+	err := ValidateStruct(&c,
+		Field(&c.Name, Required),
+		Field(&c.Address.Street, Required),
+		Field(&c.Address.City, Required),
+		Field(&c.Address.Lat, Min(1.0)),
+		Field(&c.Shipping.City, Required),
+	)
+	assert.EqualError(t, err, "address: (city: cannot be blank; lat: must be no less than 1; street: cannot be blank.); name: cannot be blank; shipping: (city: cannot be blank.).")
+	if es, ok := err.(Errors); assert.True(t, ok) {
+		assert.IsType(t, Errors{}, es["address"])
+	}
+
+	c = Customer{Name: "John", Address: Address{Street: "Main", City: "Springfield"}, Shipping: &Address{City: "Shelbyville"}}
+	err = ValidateStruct(&c,
+		Field(&c.Name, Required),
+		Field(&c.Address.Street, Required),
+		Field(&c.Address.City, Required),
+		Field(&c.Shipping.City, Required),
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateStruct_EmbeddedPointer(t *testing.T) {
+	s := Struct3{Struct2: &Struct2{}}
+	err := ValidateStruct(&s,
+		Field(&s.Field21, Required),
+		Field(&s.S1, Required),
+	)
+	assert.EqualError(t, err, "Field21: cannot be blank; S1: cannot be blank.")
+
+	// the field pointer refers to a struct other than the one being validated
+	other := Struct2{}
+	s = Struct3{}
+	err = ValidateStruct(&s, Field(&other.Field21, Required))
+	if assert.NotNil(t, err) {
+		ie, ok := err.(InternalError)
+		if assert.True(t, ok) {
+			assert.Equal(t, ErrNilEmbeddedPointer{Index: 0, Name: "Struct2"}, ie.InternalError())
+		}
+		assert.Equal(t, "field #0 cannot be found in the struct: embedded pointer Struct2 is nil", err.Error())
+	}
+}
+
 func Test_getErrorFieldName(t *testing.T) {
 	var s1 Struct1
 	v1 := reflect.ValueOf(&s1).Elem()
 
-	sf1 := findStructField(v1, reflect.ValueOf(&s1.Field1))
+	sf1, _ := findStructFieldCached(v1, reflect.ValueOf(&s1.Field1))
 	assert.NotNil(t, sf1)
-	assert.Equal(t, "Field1", getErrorFieldName(sf1))
+	assert.Equal(t, "Field1", getErrorFieldName(&sf1.field))
 
-	jsonField := findStructField(v1, reflect.ValueOf(&s1.JSONField))
+	jsonField, _ := findStructFieldCached(v1, reflect.ValueOf(&s1.JSONField))
 	assert.NotNil(t, jsonField)
-	assert.Equal(t, "some_json_field", getErrorFieldName(jsonField))
+	assert.Equal(t, "some_json_field", getErrorFieldName(&jsonField.field))
 
-	jsonIgnoredField := findStructField(v1, reflect.ValueOf(&s1.JSONIgnoredField))
+	jsonIgnoredField, _ := findStructFieldCached(v1, reflect.ValueOf(&s1.JSONIgnoredField))
 	assert.NotNil(t, jsonIgnoredField)
-	assert.Equal(t, "JSONIgnoredField", getErrorFieldName(jsonIgnoredField))
+	assert.Equal(t, "JSONIgnoredField", getErrorFieldName(&jsonIgnoredField.field))
 }
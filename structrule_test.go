@@ -0,0 +1,86 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type allocation struct {
+	Name    string `json:"name"`
+	Percent int    `json:"percent"`
+}
+
+type allocationPlan struct {
+	Owner       string       `json:"owner"`
+	Allocations []allocation `json:"allocations"`
+}
+
+func totalPercent(ctx context.Context, structPtr interface{}) error {
+	total := 0
+	for _, a := range structPtr.(*allocationPlan).Allocations {
+		total += a.Percent
+	}
+	if total != 100 {
+		return Errors{"allocations": errors.New("must add up to 100 percent")}
+	}
+	return nil
+}
+
+func TestStructRule(t *testing.T) {
+	p := &allocationPlan{Allocations: []allocation{{"a", 30}, {"b", 60}}}
+	tests := []struct {
+		tag    string
+		fields []*FieldRules
+		err    string
+	}{
+		{"t1", []*FieldRules{StructRule(totalPercent)}, "allocations: must add up to 100 percent."},
+		{"t2", []*FieldRules{Field(&p.Owner, Required), StructRule(totalPercent)}, "allocations: must add up to 100 percent; owner: cannot be blank."},
+		{"t3", []*FieldRules{StructRule(func(ctx context.Context, structPtr interface{}) error {
+			return errors.New("invalid plan")
+		})}, "_root: invalid plan."},
+		{"t4", []*FieldRules{StructRule(func(ctx context.Context, structPtr interface{}) error {
+			return nil
+		})}, ""},
+		// the errors of the fields take precedence
+		{"t5", []*FieldRules{Field(&p.Allocations, Length(3, 3)), StructRule(totalPercent)}, "allocations: the length must be exactly 3."},
+		// nested errors are merged
+		{"t6", []*FieldRules{Field(&p.Allocations, Each(By(func(value interface{}) error {
+			if value.(allocation).Percent > 50 {
+				return errors.New("too large")
+			}
+			return nil
+		}))), StructRule(func(ctx context.Context, structPtr interface{}) error {
+			return Errors{"allocations": Errors{"0": errors.New("too small")}}
+		})}, "allocations: (0: too small; 1: too large.)."},
+	}
+	for _, test := range tests {
+		err := ValidateStruct(p, test.fields...)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	// internal errors are returned as is
+	err := ValidateStruct(p, StructRule(func(ctx context.Context, structPtr interface{}) error {
+		return NewInternalError(errors.New("internal"))
+	}), Field(&p.Owner, Required))
+	if assert.Error(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+	}
+
+	// plain errors are reported under StructErrorKey
+	err = ValidateStructWithContext(context.Background(), p, StructRule(func(ctx context.Context, structPtr interface{}) error {
+		assert.NotNil(t, ctx)
+		assert.Equal(t, p, structPtr)
+		return errors.New("invalid plan")
+	}))
+	if assert.IsType(t, Errors{}, err) {
+		assert.Equal(t, "invalid plan", err.(Errors)["_root"].Error())
+	}
+
+	// deep validation skips the struct rules
+	err = ValidateStructDeep(p, StructRule(totalPercent))
+	assertError(t, "allocations: must add up to 100 percent.", err, "t7")
+}
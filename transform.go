@@ -0,0 +1,131 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// Trim is a transformation rule that removes leading and trailing white space from a string.
+	Trim = Transform(stringTransform(strings.TrimSpace))
+	// ToLower is a transformation rule that converts a string to lower case.
+	ToLower = Transform(stringTransform(strings.ToLower))
+	// ToUpper is a transformation rule that converts a string to upper case.
+	ToUpper = Transform(stringTransform(strings.ToUpper))
+	// CollapseSpace is a transformation rule that replaces each sequence of white space in a string
+	// with a single space and removes leading and trailing white space.
+	CollapseSpace = Transform(stringTransform(func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	}))
+	// NormalizeUnicode is a transformation rule that converts a string to the Unicode normalization form NFC,
+	// so that the same text is represented by the same sequence of code points.
+	NormalizeUnicode = Transform(stringTransform(norm.NFC.String))
+)
+
+type (
+	// TransformFunc represents a function that transforms a value before it is validated.
+	TransformFunc func(value interface{}) interface{}
+
+	// TransformRule is a rule that transforms the value being validated instead of validating it.
+	TransformRule struct {
+		f TransformFunc
+	}
+
+	// transformer is implemented by the rules that transform the value being validated.
+	transformer interface {
+		transform(value interface{}) interface{}
+	}
+)
+
+// Transform returns a rule that transforms the value being validated using the given function.
+// The transformed value is validated by the rules following it in the same rule list.
+// When the rule is used with Field() or Key(), the transformed value is also stored back into
+// the struct field or the map entry. For example,
+//
+//	validation.Field(&c.Email, validation.Trim, validation.ToLower, validation.Required, is.Email),
+//
+// The transformed value must be assignable or convertible to the type of the struct field or the map
+// element, or an internal error is returned. A transformation rule placed inside another rule, such as
+// When() or Each(), only affects the rules following it in the same list and is not stored back.
+func Transform(f TransformFunc) TransformRule {
+	return TransformRule{f: f}
+}
+
+// Validate does nothing as the rule only transforms values.
+func (r TransformRule) Validate(interface{}) error {
+	return nil
+}
+
+func (r TransformRule) transform(value interface{}) interface{} {
+	return r.f(value)
+}
+
+// stringTransform returns a TransformFunc that applies the given function to string values.
+// The type of a named string type is kept, and a pointer to a string is replaced with a pointer
+// to the transformed string. Values of other types are returned unchanged.
+func stringTransform(f func(string) string) TransformFunc {
+	return func(value interface{}) interface{} {
+		rv := reflect.ValueOf(value)
+		switch {
+		case rv.Kind() == reflect.String:
+			return reflect.ValueOf(f(rv.String())).Convert(rv.Type()).Interface()
+		case rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.String:
+			p := reflect.New(rv.Type().Elem())
+			p.Elem().SetString(f(rv.Elem().String()))
+			return p.Interface()
+		}
+		return value
+	}
+}
+
+// applyTransform transforms a value with the given transformer. If ptr is valid, the transformed value
+// is stored into the value ptr points to, and the stored value is returned.
+func applyTransform(t transformer, value interface{}, ptr reflect.Value) (interface{}, error) {
+	value = t.transform(value)
+	if !ptr.IsValid() {
+		return value, nil
+	}
+	v, err := convertValue(value, ptr.Type().Elem())
+	if err != nil {
+		return nil, NewInternalError(err)
+	}
+	ptr.Elem().Set(v)
+	return ptr.Elem().Interface(), nil
+}
+
+// convertValue converts a value so that it can be stored into a variable of the given type.
+// The value is converted to the type if the types are of the same kind. A value that can be assigned
+// to the element type of a pointer type is converted into a new pointer.
+func convertValue(value interface{}, t reflect.Type) (reflect.Value, error) {
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		return reflect.Zero(t), nil
+	case v.Type().AssignableTo(t):
+		return v, nil
+	case v.Kind() == t.Kind() && v.Type().ConvertibleTo(t):
+		return v.Convert(t), nil
+	case t.Kind() == reflect.Ptr && v.Type().AssignableTo(t.Elem()):
+		p := reflect.New(t.Elem())
+		p.Elem().Set(v)
+		return p, nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot assign a value of type %v to %v", v.Type(), t)
+}
+
+// hasTransformer checks if any of the given rules transforms values.
+func hasTransformer(rules []Rule) bool {
+	for _, rule := range rules {
+		if _, ok := rule.(transformer); ok {
+			return true
+		}
+	}
+	return false
+}
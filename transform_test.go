@@ -0,0 +1,134 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type emailAddress string
+
+// trimmedTag is a named string with a validation method declared with a pointer receiver,
+// so that the elements of its slices are validated through pointers to them.
+type trimmedTag string
+
+func (t *trimmedTag) Validate() error {
+	if *t == "" {
+		return errors.New("must not be empty")
+	}
+	return nil
+}
+
+func TestStringTransforms(t *testing.T) {
+	s := "  Foo  Bar "
+	tests := []struct {
+		tag      string
+		rule     transformer
+		value    interface{}
+		expected interface{}
+	}{
+		{"t1.1", Trim, "  Foo  Bar ", "Foo  Bar"},
+		{"t1.2", Trim, emailAddress(" a@b.c "), emailAddress("a@b.c")},
+		{"t1.3", Trim, 123, 123},
+		{"t1.4", Trim, nil, nil},
+		{"t2.1", ToLower, "Foo", "foo"},
+		{"t2.2", ToUpper, "Foo", "FOO"},
+		{"t3.1", CollapseSpace, " Foo \t Bar\n", "Foo Bar"},
+		{"t3.2", CollapseSpace, "", ""},
+		{"t4.1", NormalizeUnicode, "e\u0301", "\u00e9"},
+		{"t4.2", NormalizeUnicode, "\u00e9", "\u00e9"},
+		{"t5.1", Default("x"), "", "x"},
+		{"t5.2", Default("x"), "y", "y"},
+		{"t5.3", Default(20), 0, 20},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.rule.transform(test.value), test.tag)
+	}
+
+	p := Trim.transform(&s).(*string)
+	assert.Equal(t, "Foo  Bar", *p)
+	assert.Equal(t, "  Foo  Bar ", s, "the referenced string is not modified")
+}
+
+func TestTransform_Field(t *testing.T) {
+	type user struct {
+		Name     string
+		Email    emailAddress
+		Nickname *string
+		Tags     interface{}
+		PageSize int
+		Limit    *int
+	}
+
+	nickname := " Bob "
+	u := user{Name: "  John   Smith ", Email: " John@Example.COM ", Nickname: &nickname, Tags: " a "}
+	err := ValidateStruct(&u,
+		Field(&u.Name, CollapseSpace, Required, Length(1, 5)),
+		Field(&u.Email, Trim, ToLower, Required, In(emailAddress("john@example.com"))),
+		Field(&u.Nickname, Trim, Length(3, 3)),
+		Field(&u.Tags, Trim, In("a")),
+		Field(&u.PageSize, Default(20), Min(10)),
+		Field(&u.Limit, Default(5), Max(10)),
+	)
+	assertError(t, "Name: the length must be between 1 and 5.", err, "t1")
+	assert.Equal(t, "John Smith", u.Name)
+	assert.Equal(t, emailAddress("john@example.com"), u.Email)
+	assert.Equal(t, "Bob", *u.Nickname)
+	assert.Equal(t, " Bob ", nickname)
+	assert.Equal(t, "a", u.Tags)
+	assert.Equal(t, 20, u.PageSize)
+	if assert.NotNil(t, u.Limit) {
+		assert.Equal(t, 5, *u.Limit)
+	}
+
+	// a transformed value of an incompatible type
+	err = ValidateStruct(&u, Field(&u.PageSize, Transform(func(v interface{}) interface{} { return "x" })))
+	if assert.Error(t, err) {
+		_, ok := err.(InternalError)
+		assert.True(t, ok)
+		assert.Equal(t, "cannot assign a value of type string to int", err.Error())
+	}
+
+	// a transformation rule placed after a failed rule is not applied
+	u.Name = ""
+	err = ValidateStruct(&u, Field(&u.Name, Required, Default("x")))
+	assertError(t, "Name: cannot be blank.", err, "t2")
+	assert.Equal(t, "", u.Name)
+}
+
+func TestTransform_Key(t *testing.T) {
+	m := map[string]interface{}{"email": " A@B.C ", "name": "x"}
+	err := Validate(m, Map(
+		Key("email", Trim, ToLower, In("a@b.c")),
+		Key("name", Transform(func(v interface{}) interface{} { return strings.Repeat(v.(string), 3) }), Length(3, 3)),
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"email": "a@b.c", "name": "xxx"}, m)
+
+	m2 := map[string]string{"a": " x "}
+	err = Validate(m2, Map(Key("a", Trim, Length(2, 2))))
+	assertError(t, "a: the length must be exactly 2.", err, "t1")
+	assert.Equal(t, "x", m2["a"])
+
+	// a value validated directly is transformed for the following rules only
+	assert.NoError(t, Validate(" abc ", Trim, Length(3, 3)))
+}
+
+func TestTransform_Each(t *testing.T) {
+	// the elements are transformed for the following rules and their validation methods only
+	tags := []trimmedTag{" a ", "bc"}
+	assert.NoError(t, Validate(tags, Each(Trim, Length(1, 2))))
+	assert.Equal(t, []trimmedTag{" a ", "bc"}, tags)
+
+	err := Validate(tags, EachUntilFirstError(Trim, Length(2, 2)))
+	assertError(t, "0: the length must be exactly 2.", err, "t1")
+	assert.Equal(t, []trimmedTag{" a ", "bc"}, tags)
+
+	// the validation methods are called on the transformed copies
+	tags = []trimmedTag{" ", "a"}
+	err = Validate(tags, Each(Trim))
+	assertError(t, "0: must not be empty.", err, "t2")
+	assert.Equal(t, []trimmedTag{" ", "a"}, tags)
+}
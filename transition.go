@@ -0,0 +1,232 @@
+// Copyright 2016 Qiang Xue. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var (
+	// ErrTransitionStructs is the error that the values given to ValidateTransition are not pointers to structs of the same type.
+	ErrTransitionStructs = errors.New("the old and new values must be pointers to structs of the same type")
+
+	// ErrImmutable is the error that returns when an immutable value is changed.
+	ErrImmutable = NewError("validation_immutable", "cannot be changed")
+	// ErrOnlyIncrease is the error that returns when a value that may only increase is decreased.
+	ErrOnlyIncrease = NewError("validation_only_increase", "cannot be decreased")
+	// ErrTransitionNotAllowed is the error that returns when a value is changed to a value it cannot be changed to.
+	ErrTransitionNotAllowed = NewError("validation_transition_not_allowed", "cannot be changed from {{.from}} to {{.to}}")
+)
+
+type (
+	// TransitionRule is a rule that checks the change of a value against its previous value.
+	// It is only effective when used with ValidateTransition().
+	TransitionRule struct {
+		check func(old, new interface{}) (bool, error)
+		err   Error
+	}
+
+	// transitionRule is implemented by the rules that validate a value against its previous value.
+	transitionRule interface {
+		validateTransition(old, new interface{}) error
+	}
+
+	// boundTransitionRule is a transition rule bound to the previous value.
+	boundTransitionRule struct {
+		rule transitionRule
+		old  interface{}
+	}
+)
+
+// ValidateTransition validates the change of a struct from the old value to the new value, both of which must be
+// pointers to structs of the same type. The fields are specified via Field() with pointers to the fields of the
+// new value, in the same way as ValidateStruct. The transition rules associated with a field, such as Immutable(),
+// OnlyIncrease() and AllowedTransitions(), compare the field of the new value with the same field of the old value.
+// Other rules validate the field of the new value as usual. For example,
+//
+//	err := validation.ValidateTransition(ctx, oldPost, newPost,
+//	    validation.Field(&newPost.CreatedBy, validation.Immutable()),
+//	    validation.Field(&newPost.Version, validation.OnlyIncrease()),
+//	    validation.Field(&newPost.Status, validation.Required, validation.AllowedTransitions(map[Status][]Status{
+//	        StatusDraft: {StatusPublished},
+//	    })),
+//	)
+//
+// If the old value is nil, such as when the struct is being created, the transition rules are not applied.
+// The same is true for a field of a nested struct whose pointer is nil in the old value. Transition rules nested
+// in other rules, such as When(), are not applied.
+func ValidateTransition(ctx context.Context, old, new interface{}, fields ...*FieldRules) error {
+	ov, nv := reflect.ValueOf(old), reflect.ValueOf(new)
+	if nv.Kind() != reflect.Ptr || nv.IsNil() {
+		return validateStruct(ctx, new, false, fields)
+	}
+	if old != nil && (ov.Type() != nv.Type()) {
+		return NewInternalError(ErrTransitionStructs)
+	}
+	if old == nil || ov.IsNil() || nv.Elem().Kind() != reflect.Struct {
+		return validateStruct(ctx, new, false, fields)
+	}
+
+	bound := make([]*FieldRules, len(fields))
+	for i, fr := range fields {
+		bound[i] = fr
+		fv := reflect.ValueOf(fr.fieldPtr)
+		if fr.structRule != nil || fv.Kind() != reflect.Ptr || !hasTransitionRule(fr.rules) {
+			continue
+		}
+		fm, _ := findStructFieldCached(nv.Elem(), fv)
+		if fm == nil {
+			// the field error is reported by validateStruct
+			continue
+		}
+		f, err := ov.Elem().FieldByIndexErr(fm.index)
+		if err != nil {
+			// the field does not exist in the old value as it is in a nil struct pointer
+			continue
+		}
+		if !f.CanInterface() {
+			return NewInternalError(fmt.Errorf("field #%v is not exported and cannot be compared", i))
+		}
+		bound[i] = &FieldRules{fieldPtr: fr.fieldPtr, rules: bindTransitionRules(fr.rules, f.Interface()), err: fr.err}
+	}
+	return validateStruct(ctx, new, false, bound)
+}
+
+// Immutable returns a transition rule that checks if a value is not changed.
+// A nil pointer is only considered equal to another nil pointer.
+func Immutable() TransitionRule {
+	return TransitionRule{
+		check: func(old, new interface{}) (bool, error) {
+			old, oldNil := Indirect(old)
+			new, newNil := Indirect(new)
+			if oldNil || newNil {
+				return oldNil == newNil, nil
+			}
+			return valuesEqual(old, new), nil
+		},
+		err: ErrImmutable,
+	}
+}
+
+// OnlyIncrease returns a transition rule that checks if a value is not less than its previous value.
+// The values are compared in the same way as Min(), so the rule works on the types supported by Min(),
+// such as numbers and time.Time. A value that is nil or was nil is considered valid.
+func OnlyIncrease() TransitionRule {
+	return TransitionRule{
+		check: func(old, new interface{}) (bool, error) {
+			old, oldNil := Indirect(old)
+			if oldNil {
+				return true, nil
+			}
+			err := Min(old).Validate(new)
+			if _, ok := err.(Error); ok {
+				return false, nil
+			}
+			return err == nil, err
+		},
+		err: ErrOnlyIncrease,
+	}
+}
+
+// AllowedTransitions returns a transition rule that checks if a value is changed to one of the values
+// it may be changed to, as specified by the given map from the previous values to the new values.
+// An unchanged value is always valid. A value that is nil or was nil is also considered valid;
+// use other rules such as Required and In to check the value itself.
+func AllowedTransitions[T comparable](transitions map[T][]T) TransitionRule {
+	return TransitionRule{
+		check: func(old, new interface{}) (bool, error) {
+			old, oldNil := Indirect(old)
+			new, newNil := Indirect(new)
+			if oldNil || newNil {
+				return true, nil
+			}
+			from, ok := old.(T)
+			if !ok {
+				return false, fmt.Errorf("cannot convert %T to %T", old, from)
+			}
+			if interface{}(from) == new {
+				return true, nil
+			}
+			for _, to := range transitions[from] {
+				if to == new {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+		err: ErrTransitionNotAllowed,
+	}
+}
+
+// Validate does nothing as the rule needs the previous value, which is only available with ValidateTransition().
+func (r TransitionRule) Validate(interface{}) error {
+	return nil
+}
+
+// Error sets the error message for the rule.
+func (r TransitionRule) Error(message string) TransitionRule {
+	r.err = r.err.SetMessage(message)
+	return r
+}
+
+// ErrorObject sets the error struct for the rule.
+func (r TransitionRule) ErrorObject(err Error) TransitionRule {
+	r.err = err
+	return r
+}
+
+func (r TransitionRule) validateTransition(old, new interface{}) error {
+	ok, err := r.check(old, new)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		from, _ := Indirect(old)
+		to, _ := Indirect(new)
+		return r.err.SetParams(map[string]interface{}{"from": from, "to": to})
+	}
+	return nil
+}
+
+// Validate validates the value against the previous value.
+func (r boundTransitionRule) Validate(value interface{}) error {
+	return r.rule.validateTransition(r.old, value)
+}
+
+// hasTransitionRule checks if any of the given rules is a transition rule.
+func hasTransitionRule(rules []Rule) bool {
+	for _, rule := range rules {
+		if _, ok := rule.(transitionRule); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// bindTransitionRules returns a copy of the given rules with the transition rules bound to the previous value.
+func bindTransitionRules(rules []Rule, old interface{}) []Rule {
+	bound := make([]Rule, len(rules))
+	for i, rule := range rules {
+		if tr, ok := rule.(transitionRule); ok {
+			rule = boundTransitionRule{rule: tr, old: old}
+		}
+		bound[i] = rule
+	}
+	return bound
+}
+
+// valuesEqual checks if two values are equal. Times are compared by the instants they represent.
+func valuesEqual(a, b interface{}) bool {
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Equal(tb)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,115 @@
+package validation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type postStatus string
+
+type postMeta struct {
+	Revision int `json:"revision"`
+}
+
+type post struct {
+	ID        int        `json:"id"`
+	Status    postStatus `json:"status"`
+	CreatedBy *string    `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	Meta      *postMeta  `json:"meta"`
+}
+
+var postTransitions = map[postStatus][]postStatus{
+	"draft":     {"published", "archived"},
+	"published": {"archived"},
+}
+
+func TestValidateTransition(t *testing.T) {
+	alice, bob := "alice", "bob"
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	old := &post{ID: 1, Status: "draft", CreatedBy: &alice, CreatedAt: now, Meta: &postMeta{Revision: 3}}
+
+	rules := func(p *post) []*FieldRules {
+		return []*FieldRules{
+			Field(&p.ID, Required, Immutable()),
+			Field(&p.Status, Required, AllowedTransitions(postTransitions)),
+			Field(&p.CreatedBy, Immutable()),
+			Field(&p.CreatedAt, Immutable()),
+		}
+	}
+	tests := []struct {
+		tag    string
+		change func(p *post)
+		err    string
+	}{
+		{"t1", func(p *post) {}, ""},
+		{"t2", func(p *post) { p.Status = "published" }, ""},
+		{"t3", func(p *post) { p.Status = "unknown" }, "status: cannot be changed from draft to unknown."},
+		{"t4", func(p *post) { p.ID = 2; p.Status = "" }, "id: cannot be changed; status: cannot be blank."},
+		{"t5", func(p *post) { p.CreatedBy = &bob }, "created_by: cannot be changed."},
+		{"t6", func(p *post) { p.CreatedBy = nil }, "created_by: cannot be changed."},
+		{"t7", func(p *post) { s := "alice"; p.CreatedBy = &s }, ""},
+		{"t8", func(p *post) { p.CreatedAt = now.In(time.FixedZone("X", 3600)) }, ""},
+		{"t9", func(p *post) { p.CreatedAt = now.Add(time.Second) }, "created_at: cannot be changed."},
+	}
+	for _, test := range tests {
+		p := *old
+		test.change(&p)
+		err := ValidateTransition(context.Background(), old, &p, rules(&p)...)
+		assertError(t, test.err, err, test.tag)
+	}
+
+	// transitions from a later status
+	published := &post{ID: 1, Status: "published"}
+	p := post{ID: 1, Status: "draft"}
+	err := ValidateTransition(nil, published, &p, Field(&p.Status, AllowedTransitions(postTransitions)))
+	assertError(t, "status: cannot be changed from published to draft.", err, "t10")
+
+	// no old value
+	p = post{ID: 1, Status: "unknown"}
+	err = ValidateTransition(context.Background(), nil, &p, rules(&p)...)
+	assertError(t, "", err, "t11")
+	err = ValidateTransition(context.Background(), (*post)(nil), &p, Field(&p.ID, Required, Immutable()))
+	assertError(t, "", err, "t12")
+
+	// transition rules are not applied by ValidateStruct
+	assert.NoError(t, ValidateStruct(&p, rules(&p)...))
+
+	// mismatched types
+	err = ValidateTransition(context.Background(), &postMeta{}, &p)
+	assert.Equal(t, NewInternalError(ErrTransitionStructs), err)
+}
+
+func TestValidateTransition_NestedAndOnlyIncrease(t *testing.T) {
+	old := &post{Meta: &postMeta{Revision: 3}}
+	p := post{Meta: &postMeta{Revision: 2}}
+	err := ValidateTransition(context.Background(), old, &p, Field(&p.Meta.Revision, OnlyIncrease()))
+	assertError(t, "meta: (revision: cannot be decreased.).", err, "t1")
+
+	p.Meta.Revision = 3
+	assert.NoError(t, ValidateTransition(context.Background(), old, &p, Field(&p.Meta.Revision, OnlyIncrease())))
+
+	// the nested struct does not exist in the old value
+	old.Meta = nil
+	p.Meta.Revision = 1
+	assert.NoError(t, ValidateTransition(context.Background(), old, &p, Field(&p.Meta.Revision, OnlyIncrease())))
+
+	// custom error messages
+	old = &post{ID: 1, CreatedAt: time.Now()}
+	p = post{ID: 2, CreatedAt: old.CreatedAt.Add(-time.Hour)}
+	err = ValidateTransition(context.Background(), old, &p,
+		Field(&p.ID, Immutable().Error("is read-only")),
+		Field(&p.CreatedAt, OnlyIncrease().ErrorObject(NewError("code", "cannot go back in time"))),
+	)
+	assertError(t, "created_at: cannot go back in time; id: is read-only.", err, "t2")
+
+	// comparison errors
+	p = post{ID: 1, Status: "a"}
+	err = ValidateTransition(context.Background(), &post{Status: "b"}, &p, Field(&p.Status, OnlyIncrease()))
+	assertError(t, "status: type not supported: validation.postStatus.", err, "t3")
+	err = ValidateTransition(context.Background(), &post{Status: "b"}, &p, Field(&p.Status, AllowedTransitions(map[string][]string{})))
+	assertError(t, "status: cannot convert validation.postStatus to string.", err, "t4")
+}
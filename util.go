@@ -9,11 +9,15 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 )
 
 var (
 	bytesType  = reflect.TypeOf([]byte(nil))
 	valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+	// keptValuerTypes are the types registered via KeepValuer (true) and UseDriverValue (false).
+	keptValuerTypes sync.Map // reflect.Type => bool
 )
 
 // EnsureString ensures the given value is a string.
@@ -90,13 +94,47 @@ func ToFloat(value interface{}) (float64, error) {
 
 // IsEmpty checks if a value is empty or not.
 // A value is considered empty if
+// - a function is registered for its type via RegisterEmptyFunc: the function returns true
+// - it implements Emptier: IsEmpty() returns true
+// - it has an IsZero() bool method, such as time.Time: IsZero() returns true
 // - integer, float: zero
 // - bool: false
 // - string, array: len() == 0
 // - slice, map: nil or len() == 0
+// - struct: all fields are zero
 // - interface, pointer: nil or the referenced value is empty
+// The IsEmpty and IsZero methods may be declared with a pointer receiver.
 func IsEmpty(value interface{}) bool {
+	if !emptyFuncsUsed.Load() {
+		// the most common types are checked without reflection
+		switch x := value.(type) {
+		case string:
+			return x == ""
+		case int:
+			return x == 0
+		case int64:
+			return x == 0
+		case float64:
+			return x == 0
+		case bool:
+			return !x
+		}
+	}
+
 	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return true
+		}
+	}
+
+	if empty, ok := isEmptyByType(value, v); ok {
+		return empty
+	}
+
 	switch v.Kind() {
 	case reflect.String, reflect.Array, reflect.Map, reflect.Slice:
 		return v.Len() == 0
@@ -108,12 +146,7 @@ func IsEmpty(value interface{}) bool {
 		return v.Uint() == 0
 	case reflect.Float32, reflect.Float64:
 		return v.Float() == 0
-	case reflect.Invalid:
-		return true
 	case reflect.Interface, reflect.Ptr:
-		if v.IsNil() {
-			return true
-		}
 		return IsEmpty(v.Elem().Interface())
 	case reflect.Struct:
 		return v.IsZero()
@@ -124,10 +157,21 @@ func IsEmpty(value interface{}) bool {
 
 // Indirect returns the value that the given interface or pointer references to.
 // If the value implements driver.Valuer, it will deal with the value returned by
-// the Value() method instead. A boolean value is also returned to indicate if
-// the value is nil or not (only applicable to interface, pointer, map, and slice).
+// the Value() method instead, with the following exceptions:
+// - sql.Null[T] and the sql.NullString family: the inner value is used, keeping its Go type
+// - named types of bool, numeric and string kinds with driver values of the same kind: the value is used as is
+// - types registered via KeepValuer: the value is used as is
+// Named scalar types registered via UseDriverValue are always converted into their driver values.
+// If the value is an Optional or a Nullable, it will deal with
+// the value being held, or treat the value as nil if it is not present or is null.
+// A boolean value is also returned to indicate if the value is nil or not
+// (only applicable to interface, pointer, map, slice, Optional and Nullable).
 // If the value is neither an interface nor a pointer, it will be returned back.
 func Indirect(value interface{}) (interface{}, bool) {
+	if isPredeclared(value) {
+		// values of predeclared types are neither pointers nor have methods
+		return value, false
+	}
 	rv := reflect.ValueOf(value)
 	kind := rv.Kind()
 	switch kind {
@@ -144,16 +188,83 @@ func Indirect(value interface{}) (interface{}, bool) {
 		}
 	}
 
+	if p, ok := value.(presenceTracker); ok {
+		if present, null := p.presence(); !present || null {
+			return nil, true
+		}
+		return Indirect(p.innerValue())
+	}
+
 	if rv.Type().Implements(valuerType) {
-		return indirectValuer(value.(driver.Valuer))
+		return indirectValuer(value, rv)
 	}
 
 	return value, false
 }
 
-func indirectValuer(valuer driver.Valuer) (interface{}, bool) {
-	if value, err := valuer.Value(); value != nil && err == nil {
-		return Indirect(value)
+func indirectValuer(value interface{}, rv reflect.Value) (interface{}, bool) {
+	t := rv.Type()
+	keep, registered := keptValuerTypes.Load(t)
+	if registered && keep.(bool) {
+		return value, false
+	}
+	if isSQLNullType(t) {
+		if !rv.Field(1).Bool() {
+			return nil, true
+		}
+		return Indirect(rv.Field(0).Interface())
+	}
+	v, err := value.(driver.Valuer).Value()
+	if v == nil || err != nil {
+		return nil, true
+	}
+	if !registered && scalarClass(t.Kind()) != 0 && scalarClass(t.Kind()) == scalarClass(reflect.TypeOf(v).Kind()) {
+		// keep the type of a named scalar type, which would be lost by converting it into a driver value
+		return value, false
+	}
+	return Indirect(v)
+}
+
+// scalarClass returns the class of a bool, numeric or string kind, so that the kinds of a named scalar type and
+// its driver value can be matched. Integer kinds are of the same class, as integers become int64 driver values.
+// It returns 0 for other kinds.
+func scalarClass(k reflect.Kind) int {
+	switch k {
+	case reflect.Bool:
+		return 1
+	case reflect.String:
+		return 2
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return 3
+	case reflect.Float32, reflect.Float64:
+		return 4
+	}
+	return 0
+}
+
+// isSQLNullType checks if a type is sql.Null[T] or one of the sql.NullString family,
+// which hold the value in the first field and the validity in the second field named Valid.
+func isSQLNullType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == "database/sql" && t.NumField() == 2 &&
+		t.Field(1).Name == "Valid" && t.Field(1).Type.Kind() == reflect.Bool && t.Field(0).IsExported()
+}
+
+// KeepValuer registers the types of the given values so that Indirect returns their values as they are,
+// instead of calling their driver.Valuer Value() method. Use it for types implementing driver.Valuer
+// that should be validated as themselves, such as types implementing Validatable or Comparable.
+func KeepValuer(values ...interface{}) {
+	for _, value := range values {
+		keptValuerTypes.Store(reflect.TypeOf(value), true)
+	}
+}
+
+// UseDriverValue registers the types of the given values so that Indirect always returns the value returned
+// by their driver.Valuer Value() method. Use it for named types of bool, numeric and string kinds that should be
+// validated as their driver values, which are otherwise validated as themselves if the driver values are of
+// the same kind.
+func UseDriverValue(values ...interface{}) {
+	for _, value := range values {
+		keptValuerTypes.Store(reflect.TypeOf(value), false)
 	}
-	return nil, true
 }
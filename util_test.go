@@ -6,6 +6,7 @@ package validation
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"testing"
 	"time"
 
@@ -301,3 +302,106 @@ func TestIndirect(t *testing.T) {
 		assert.Equal(t, test.isNil, isNil, test.tag)
 	}
 }
+
+type (
+	// valuerStatus is a named string implementing driver.Valuer.
+	valuerStatus string
+
+	// valuerJSON is a struct implementing driver.Valuer.
+	valuerJSON struct {
+		Name string
+	}
+
+	// keptValuer is a struct implementing driver.Valuer that is validated as itself.
+	keptValuer struct {
+		Name string
+	}
+
+	// keptStatus is a named string implementing driver.Valuer that is validated as itself.
+	keptStatus string
+
+	// valuerEnum is a named int implementing driver.Valuer with its name as the driver value.
+	valuerEnum int
+
+	// driverStatus is a named string implementing driver.Valuer that is validated as its driver value.
+	driverStatus string
+)
+
+func (s valuerStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+func (j valuerJSON) Value() (driver.Value, error) {
+	if j.Name == "" {
+		return nil, nil
+	}
+	return []byte(`{"name":"` + j.Name + `"}`), nil
+}
+
+func (k keptValuer) Value() (driver.Value, error) {
+	return k.Name, nil
+}
+
+func (s keptStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+func (e valuerEnum) Value() (driver.Value, error) {
+	return [...]string{"inactive", "active"}[e], nil
+}
+
+func (s driverStatus) Value() (driver.Value, error) {
+	return "status:" + string(s), nil
+}
+
+func TestIndirect_Valuer(t *testing.T) {
+	KeepValuer(keptValuer{}, keptStatus(""))
+	UseDriverValue(driverStatus(""))
+	now := time.Now()
+
+	tests := []struct {
+		tag    string
+		value  interface{}
+		result interface{}
+		isNil  bool
+	}{
+		{"t1", sql.NullInt32{Int32: 5, Valid: true}, int32(5), false},
+		{"t2", sql.NullInt16{Int16: 5, Valid: true}, int16(5), false},
+		{"t3", sql.NullByte{Byte: 5, Valid: true}, byte(5), false},
+		{"t4", sql.NullFloat64{Float64: 1.5, Valid: true}, 1.5, false},
+		{"t5", sql.NullBool{Bool: true, Valid: true}, true, false},
+		{"t6", sql.NullTime{Time: now, Valid: true}, now, false},
+		{"t7", sql.NullString{String: "a", Valid: true}, "a", false},
+		{"t8", sql.NullInt32{Int32: 5}, nil, true},
+		{"t9", sql.Null[valuerStatus]{V: "active", Valid: true}, valuerStatus("active"), false},
+		{"t10", sql.Null[valuerStatus]{V: "active"}, nil, true},
+		{"t11", &sql.Null[int8]{V: 3, Valid: true}, int8(3), false},
+		{"t12", sql.Null[*int]{Valid: true}, nil, true},
+		{"t13", valuerStatus("active"), valuerStatus("active"), false},
+		{"t14", valuerJSON{Name: "a"}, []byte(`{"name":"a"}`), false},
+		{"t15", valuerJSON{}, nil, true},
+		{"t16", keptValuer{Name: "a"}, keptValuer{Name: "a"}, false},
+		{"t17", keptStatus("active"), keptStatus("active"), false},
+		{"t18", sql.Null[keptStatus]{V: "active", Valid: true}, keptStatus("active"), false},
+		{"t19", valuerEnum(1), "active", false},
+		{"t20", driverStatus("active"), "status:active", false},
+		{"t21", sql.Null[driverStatus]{V: "active", Valid: true}, "status:active", false},
+	}
+
+	for _, test := range tests {
+		result, isNil := Indirect(test.value)
+		assert.Equal(t, test.result, result, test.tag)
+		assert.Equal(t, test.isNil, isNil, test.tag)
+	}
+
+	// rules see the inner Go types
+	assert.Nil(t, Min(int32(5)).Validate(sql.NullInt32{Int32: 5, Valid: true}))
+	assert.Nil(t, In(valuerStatus("active")).Validate(valuerStatus("active")))
+	assert.Nil(t, In(valuerStatus("active")).Validate(sql.Null[valuerStatus]{V: "active", Valid: true}))
+	assert.Nil(t, In(keptStatus("active")).Validate(keptStatus("active")))
+	// named scalar types are validated as their driver values if these are of other kinds or if registered
+	// via UseDriverValue
+	assert.Nil(t, In("active").Validate(valuerEnum(1)))
+	assert.Nil(t, In("status:active").Validate(driverStatus("active")))
+	assert.Equal(t, ErrRequired, Required.Validate(sql.Null[string]{V: "a"}))
+}
@@ -61,43 +61,28 @@ var (
 //
 // Validate performs validation using the following steps:
 //  1. For each rule, call its `Validate()` to validate the value. Return if any error is found.
+//     A transformation rule, such as Trim, transforms the value validated by the rules following it.
 //  2. If the value being validated implements `Validatable`, call the value's `Validate()`.
 //     Return with the validation result.
 //  3. If the value being validated is a map/slice/array, and the element type implements `Validatable`,
 //     for each element call the element value's `Validate()`. Return with the validation result.
+//
+// If a type implements `Validatable` with a pointer receiver, its `Validate()` is also called for values
+// that are addressable: elements of slices, struct fields specified via Field(), and values referenced by pointers.
+// Elements of maps and arrays that are not addressable are copied before calling `Validate()`, so changes
+// made by the method are not stored back. A value of such a type passed directly to Validate is not addressable
+// and is not validated; pass a pointer to it instead.
+//
+// If a value implementing `Validatable` also implements `BeforeValidator` or `AfterValidator`,
+// its `BeforeValidate()` is called before its validation method and its `AfterValidate()` is called after it,
+// which allows the value to normalize itself and to add errors keyed by field names.
+//
+// The nesting depth is limited by MaxDepth, and a pointer, map or slice that is encountered again while it is
+// being validated is considered valid, so that cyclic data does not cause infinite recursion. The validation
+// started by the `Validate()` method of a value is a new one, so the depth and the cycles are only tracked across
+// such methods by context-aware validation. See ValidateWithContext.
 func Validate(value interface{}, rules ...Rule) error {
-	for _, rule := range rules {
-		if s, ok := rule.(skipRule); ok && s.skip {
-			return nil
-		}
-		if err := rule.Validate(value); err != nil {
-			return err
-		}
-	}
-
-	rv := reflect.ValueOf(value)
-	if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
-		return nil
-	}
-
-	if v, ok := value.(Validatable); ok {
-		return v.Validate()
-	}
-
-	switch rv.Kind() {
-	case reflect.Map:
-		if rv.Type().Elem().Implements(validatableType) {
-			return validateMap(rv)
-		}
-	case reflect.Slice, reflect.Array:
-		if rv.Type().Elem().Implements(validatableType) {
-			return validateSlice(rv)
-		}
-	case reflect.Ptr, reflect.Interface:
-		return Validate(rv.Elem().Interface())
-	}
-
-	return nil
+	return validate(nil, stateOf(nil), value, reflect.Value{}, rules)
 }
 
 // ValidateWithContext validates the given value with the given context and returns the validation error, if any.
@@ -113,96 +98,200 @@ func Validate(value interface{}, rules ...Rule) error {
 //     for each element call the element value's `ValidateWithContext()`. Return with the validation result.
 //  5. If the value being validated is a map/slice/array, and the element type implements `Validatable`,
 //     for each element call the element value's `Validate()`. Return with the validation result.
+//
+// Pointer receiver implementations and hooks are handled in the same way as described in Validate.
+// The context passed to the validation methods and the context-aware rules carries the nesting depth and the values
+// being validated, so the validations they start with the context continue to be subject to the depth limit and
+// the cycle detection.
 func ValidateWithContext(ctx context.Context, value interface{}, rules ...Rule) error {
+	return validate(ctx, stateOf(ctx), value, reflect.Value{}, rules)
+}
+
+// validate validates a value with the given rules and the value's own validation method, at the nesting level
+// following the one of the given state. If ptr is valid, it points to the value and is used to call validation
+// methods declared with a pointer receiver. The values transformed by transformation rules are stored back through ptr.
+// If ctx is nil, the context-aware rules and validation methods are not used.
+func validate(ctx context.Context, st validationState, value interface{}, ptr reflect.Value, rules []Rule) error {
+	st, err := st.enter()
+	if err != nil {
+		return err
+	}
+
 	for _, rule := range rules {
 		if s, ok := rule.(skipRule); ok && s.skip {
 			return nil
 		}
-		if rc, ok := rule.(RuleWithContext); ok {
-			if err := rc.ValidateWithContext(ctx, value); err != nil {
+		if t, ok := rule.(transformer); ok {
+			var err error
+			if value, err = applyTransform(t, value, ptr); err != nil {
 				return err
 			}
-		} else if err := rule.Validate(value); err != nil {
+			continue
+		}
+		if err := applyRule(ctx, st, rule, value); err != nil {
 			return err
 		}
 	}
 
 	rv := reflect.ValueOf(value)
-	if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+	if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() || !rv.IsValid() {
+		return nil
+	}
+	if isPredeclared(value) {
+		// values of predeclared types, such as string and int, have neither methods nor nested values
+		return nil
+	}
+	if _, ok := value.(presenceTracker); !ok && !mayContainValidatable(rv.Type()) {
+		// neither the value nor the values nested in it have validation methods
 		return nil
 	}
+	return validateNested(ctx, st, value, rv, ptr)
+}
 
-	if v, ok := value.(ValidatableWithContext); ok {
-		return v.ValidateWithContext(ctx)
+// validateNested calls the validation method of a value, or the validation methods of the values nested in it.
+func validateNested(ctx context.Context, st validationState, value interface{}, rv, ptr reflect.Value) error {
+	if p, ok := value.(presenceTracker); ok {
+		// validate the value held by an Optional or a Nullable
+		if present, null := p.presence(); !present || null {
+			return nil
+		}
+		return validate(ctx, st, p.innerValue(), reflect.Value{}, nil)
 	}
 
-	if v, ok := value.(Validatable); ok {
-		return v.Validate()
+	if key, ok := pointerKey(rv); ok {
+		if st, ok = st.visit(key); !ok {
+			// the value is already being validated, i.e., the data is cyclic
+			return nil
+		}
+	}
+
+	if ok, err := callValidatable(ctx, st, rv, ptr); ok {
+		return err
 	}
 
 	switch rv.Kind() {
 	case reflect.Map:
-		if rv.Type().Elem().Implements(validatableWithContextType) {
-			return validateMapWithContext(ctx, rv)
-		}
-		if rv.Type().Elem().Implements(validatableType) {
-			return validateMap(rv)
+		if elementsValidatable(ctx, rv.Type().Elem()) {
+			return validateMap(ctx, st, rv)
 		}
 	case reflect.Slice, reflect.Array:
-		if rv.Type().Elem().Implements(validatableWithContextType) {
-			return validateSliceWithContext(ctx, rv)
-		}
-		if rv.Type().Elem().Implements(validatableType) {
-			return validateSlice(rv)
+		if elementsValidatable(ctx, rv.Type().Elem()) {
+			if rv.Kind() == reflect.Array && ptr.IsValid() && ptr.Elem().Type() == rv.Type() {
+				// use the addressable array so that its elements are addressable too
+				rv = ptr.Elem()
+			}
+			return validateSlice(ctx, st, rv)
 		}
-	case reflect.Ptr, reflect.Interface:
-		return ValidateWithContext(ctx, rv.Elem().Interface())
+	case reflect.Ptr:
+		return validate(ctx, st, rv.Elem().Interface(), rv, nil)
+	case reflect.Interface:
+		return validate(ctx, st, rv.Elem().Interface(), reflect.Value{}, nil)
 	}
 
 	return nil
 }
 
-// validateMap validates a map of validatable elements
-func validateMap(rv reflect.Value) error {
-	errs := Errors{}
-	for _, key := range rv.MapKeys() {
-		if mv := rv.MapIndex(key).Interface(); mv != nil {
-			if err := mv.(Validatable).Validate(); err != nil {
-				errs[fmt.Sprintf("%v", key.Interface())] = err
+// applyRule validates a value with a single rule. The built-in rules validating nested values are given the
+// validation state. The context-aware rules are given the context carrying the state if the validation is
+// context-aware, unless the value is of a predeclared type and thus cannot lead to nested validations.
+func applyRule(ctx context.Context, st validationState, rule Rule, value interface{}) error {
+	if nr, ok := rule.(nestingRule); ok {
+		return nr.validateWithState(ctx, st, value)
+	}
+	if ctx != nil {
+		if rc, ok := rule.(RuleWithContext); ok {
+			if !isPredeclared(value) {
+				ctx = withState(ctx, st)
 			}
+			return rc.ValidateWithContext(ctx, value)
 		}
 	}
-	if len(errs) > 0 {
-		return errs
+	return rule.Validate(value)
+}
+
+// isPredeclared checks if the given value is of a predeclared type, such as string, int or bool.
+// Such values have neither methods nor nested values.
+func isPredeclared(value interface{}) bool {
+	switch value.(type) {
+	case bool, string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr,
+		float32, float64, complex64, complex128:
+		return true
 	}
-	return nil
+	return false
 }
 
-// validateMapWithContext validates a map of validatable elements with the given context.
-func validateMapWithContext(ctx context.Context, rv reflect.Value) error {
-	errs := Errors{}
-	for _, key := range rv.MapKeys() {
-		if mv := rv.MapIndex(key).Interface(); mv != nil {
-			if err := mv.(ValidatableWithContext).ValidateWithContext(ctx); err != nil {
-				errs[fmt.Sprintf("%v", key.Interface())] = err
+// callValidatable calls the validation method of the first of the given values that implements ValidatableWithContext
+// (only for context-aware validation) or Validatable. The hooks implemented by the values are called around the method.
+// The context-aware method is given the context carrying the validation state. Invalid values are ignored.
+// The first return value indicates whether the method is called.
+func callValidatable(ctx context.Context, st validationState, vs ...reflect.Value) (bool, error) {
+	for _, v := range vs {
+		if !v.IsValid() || !v.CanInterface() {
+			continue
+		}
+		if ctx != nil {
+			if vc, ok := v.Interface().(ValidatableWithContext); ok {
+				return true, callWithHooks(ctx, st, vs, vc.ValidateWithContext)
 			}
 		}
+		if vv, ok := v.Interface().(Validatable); ok {
+			return true, callWithHooks(ctx, st, vs, func(context.Context) error { return vv.Validate() })
+		}
 	}
-	if len(errs) > 0 {
-		return errs
+	return false, nil
+}
+
+// validateElement calls the validation method of a map/slice/array element.
+// If the method is declared with a pointer receiver, it is called on the element's address
+// or on a copy of the element if the element is not addressable.
+func validateElement(ctx context.Context, st validationState, v reflect.Value) error {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil
 	}
-	return nil
+	if key, ok := pointerKey(v); ok {
+		if st, ok = st.visit(key); !ok {
+			// the element is already being validated, i.e., the data is cyclic
+			return nil
+		}
+	}
+	_, err := callValidatable(ctx, st, v, addressOf(v))
+	return err
 }
 
-// validateSlice validates a slice/array of validatable elements
-func validateSlice(rv reflect.Value) error {
+// addressOf returns a pointer to the given value. If the value is not addressable,
+// a pointer to a copy of the value is returned.
+func addressOf(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v.Addr()
+	}
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p
+}
+
+// elementsValidatable checks if the elements of the given type should be validated by calling their validation methods.
+func elementsValidatable(ctx context.Context, t reflect.Type) bool {
+	pt := reflect.PtrTo(t)
+	if ctx != nil && (t.Implements(validatableWithContextType) || pt.Implements(validatableWithContextType)) {
+		return true
+	}
+	return t.Implements(validatableType) || pt.Implements(validatableType)
+}
+
+// implementsValidatable checks if the given type implements Validatable or ValidatableWithContext.
+func implementsValidatable(t reflect.Type) bool {
+	return t.Implements(validatableType) || t.Implements(validatableWithContextType)
+}
+
+// validateMap validates a map of validatable elements.
+func validateMap(ctx context.Context, st validationState, rv reflect.Value) error {
 	errs := Errors{}
-	l := rv.Len()
-	for i := 0; i < l; i++ {
-		if ev := rv.Index(i).Interface(); ev != nil {
-			if err := ev.(Validatable).Validate(); err != nil {
-				errs[strconv.Itoa(i)] = err
+	for _, key := range rv.MapKeys() {
+		if err := validateElement(ctx, st, rv.MapIndex(key)); err != nil {
+			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+				return prependPath(err, fmt.Sprintf("%v", key.Interface()))
 			}
+			errs[fmt.Sprintf("%v", key.Interface())] = err
 		}
 	}
 	if len(errs) > 0 {
@@ -211,15 +300,16 @@ func validateSlice(rv reflect.Value) error {
 	return nil
 }
 
-// validateSliceWithContext validates a slice/array of validatable elements with the given context.
-func validateSliceWithContext(ctx context.Context, rv reflect.Value) error {
+// validateSlice validates a slice/array of validatable elements.
+func validateSlice(ctx context.Context, st validationState, rv reflect.Value) error {
 	errs := Errors{}
 	l := rv.Len()
 	for i := 0; i < l; i++ {
-		if ev := rv.Index(i).Interface(); ev != nil {
-			if err := ev.(ValidatableWithContext).ValidateWithContext(ctx); err != nil {
-				errs[strconv.Itoa(i)] = err
+		if err := validateElement(ctx, st, rv.Index(i)); err != nil {
+			if ie, ok := err.(InternalError); ok && ie.InternalError() != nil {
+				return prependPath(err, strconv.Itoa(i))
 			}
+			errs[strconv.Itoa(i)] = err
 		}
 	}
 	if len(errs) > 0 {
@@ -268,3 +268,70 @@ func (s StringValidateContext) ValidateWithContext(context.Context) error {
 	}
 	return nil
 }
+
+type PtrModel struct {
+	A string
+}
+
+func (m *PtrModel) Validate() error {
+	return ValidateStruct(m, Field(&m.A, Required))
+}
+
+type PtrModelContext struct {
+	A string
+}
+
+func (m *PtrModelContext) ValidateWithContext(ctx context.Context) error {
+	return ValidateStructWithContext(ctx, m, Field(&m.A, &validateContextAbc{}))
+}
+
+func TestValidate_PointerReceiver(t *testing.T) {
+	slice := []PtrModel{{A: "a"}, {}}
+	array := [2]PtrModel{{}, {A: "a"}}
+	mp := map[string]PtrModel{"x": {}, "y": {A: "a"}}
+	ctxSlice := []PtrModelContext{{A: "abc"}, {A: "xyz"}}
+	var nilPtr *PtrModel
+	tests := []struct {
+		tag            string
+		value          interface{}
+		err            string
+		errWithContext string
+	}{
+		{"t1", PtrModel{}, "", ""},
+		{"t2", &PtrModel{}, "A: cannot be blank.", "A: cannot be blank."},
+		{"t3", nilPtr, "", ""},
+		{"t4", slice, "1: (A: cannot be blank.).", "1: (A: cannot be blank.)."},
+		{"t5", &slice, "1: (A: cannot be blank.).", "1: (A: cannot be blank.)."},
+		{"t6", array, "0: (A: cannot be blank.).", "0: (A: cannot be blank.)."},
+		{"t7", &array, "0: (A: cannot be blank.).", "0: (A: cannot be blank.)."},
+		{"t8", mp, "x: (A: cannot be blank.).", "x: (A: cannot be blank.)."},
+		{"t9", ctxSlice, "", "1: (A: error abc.)."},
+		{"t10", []*PtrModel{nil, {}}, "1: (A: cannot be blank.).", "1: (A: cannot be blank.)."},
+	}
+	for _, test := range tests {
+		err := Validate(test.value)
+		assertError(t, test.err, err, test.tag)
+		err = ValidateWithContext(context.Background(), test.value)
+		assertError(t, test.errWithContext, err, test.tag)
+	}
+
+	// struct fields are addressable
+	s := struct {
+		M  PtrModel
+		MC PtrModelContext
+	}{MC: PtrModelContext{A: "xyz"}}
+	err := ValidateStruct(&s, Field(&s.M), Field(&s.MC))
+	assert.EqualError(t, err, "M: (A: cannot be blank.).")
+	err = ValidateStructWithContext(context.Background(), &s, Field(&s.M), Field(&s.MC))
+	assert.EqualError(t, err, "M: (A: cannot be blank.); MC: (A: error abc.).")
+	err = ValidateStruct(&s, Field(&s.M, Skip))
+	assert.NoError(t, err)
+
+	// Each passes addressable elements
+	err = Validate(slice, Each(NotNil))
+	assert.EqualError(t, err, "1: (A: cannot be blank.).")
+	err = Validate(mp, Each())
+	assert.EqualError(t, err, "x: (A: cannot be blank.).")
+	err = Validate(slice, EachUntilFirstError())
+	assert.EqualError(t, err, "1: (A: cannot be blank.).")
+}
@@ -1,6 +1,9 @@
 package validation
 
-import "context"
+import (
+	"context"
+	"reflect"
+)
 
 // When returns a validation rule that executes the given list of rules when the condition is true.
 func When(condition bool, rules ...Rule) WhenRule {
@@ -25,17 +28,14 @@ func (r WhenRule) Validate(value interface{}) error {
 
 // ValidateWithContext checks if the condition is true and if so, it validates the value using the specified rules.
 func (r WhenRule) ValidateWithContext(ctx context.Context, value interface{}) error {
-	if r.condition {
-		if ctx == nil {
-			return Validate(value, r.rules...)
-		}
-		return ValidateWithContext(ctx, value, r.rules...)
-	}
+	return r.validateWithState(ctx, stateOf(ctx), value)
+}
 
-	if ctx == nil {
-		return Validate(value, r.elseRules...)
+func (r WhenRule) validateWithState(ctx context.Context, st validationState, value interface{}) error {
+	if r.condition {
+		return validate(ctx, st, value, reflect.Value{}, r.rules)
 	}
-	return ValidateWithContext(ctx, value, r.elseRules...)
+	return validate(ctx, st, value, reflect.Value{}, r.elseRules)
 }
 
 // Else returns a validation rule that executes the given list of rules when the condition is false.